@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"order-microsystem/api-service/internal/domain/model"
+	"strconv"
+)
+
+func (c *InventoryController) CreateCategory(ctx *gin.Context) {
+	var req model.CreateCategoryReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.inventoryProxy.CreateCategory(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"category": resp})
+}
+
+// ListCategories 返回全部分类，客户端按 parent_id 组装分类树
+func (c *InventoryController) ListCategories(ctx *gin.Context) {
+	resp, err := c.inventoryProxy.ListCategories(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *InventoryController) DeleteCategory(ctx *gin.Context) {
+	categoryID, ok := catalogIDParam(ctx, "invalid category id")
+	if !ok {
+		return
+	}
+
+	if err := c.inventoryProxy.DeleteCategory(ctx.Request.Context(), categoryID); err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *InventoryController) CreateParentProduct(ctx *gin.Context) {
+	var req model.CreateParentProductReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.inventoryProxy.CreateParentProduct(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"parent_product": resp})
+}
+
+// GetParentProduct 返回父产品及其各规格的产品
+func (c *InventoryController) GetParentProduct(ctx *gin.Context) {
+	parentProductID, ok := catalogIDParam(ctx, "invalid parent product id")
+	if !ok {
+		return
+	}
+
+	resp, err := c.inventoryProxy.GetParentProduct(ctx.Request.Context(), parentProductID)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"parent_product": resp})
+}
+
+// catalogIDParam 解析路径中的分类或父产品ID，解析失败时直接返回 400
+func catalogIDParam(ctx *gin.Context, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return 0, false
+	}
+	return id, true
+}
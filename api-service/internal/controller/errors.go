@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// idempotencyKeyReusedReason 订单服务在幂等键被用于不同请求内容时附带的 ErrorInfo 原因
+const idempotencyKeyReusedReason = "IDEMPOTENCY_KEY_REUSED"
+
+// httpStatusFromError 将下游 gRPC 错误码映射为 HTTP 状态码，无法识别的错误统一返回 500
+func httpStatusFromError(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorReason 返回下游 gRPC 错误附带的 ErrorInfo 原因，没有附带时返回空字符串
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
@@ -1,8 +1,15 @@
 package controller
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"order-microsystem/api-service/internal/domain/model"
 	"order-microsystem/api-service/internal/proxy"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type InventoryController struct {
@@ -15,23 +22,249 @@ func NewInventoryController(inventoryController *proxy.InventoryProxy) *Inventor
 	}
 }
 
+// GetAllInventory 分页查询库存，支持按产品名、单价、在库数量过滤和排序
 func (c *InventoryController) GetAllInventory(ctx *gin.Context) {
-	var req struct {
-		Offset int32 `form:"offset"`
-		Limit  int32 `form:"limit"`
+	var req model.ListInventoryReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.inventoryProxy.GetAllInventory(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *InventoryController) CreateProduct(ctx *gin.Context) {
+	var req model.CreateProductReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.inventoryProxy.CreateProduct(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"product": resp})
+}
+
+func (c *InventoryController) GetProduct(ctx *gin.Context) {
+	productID, ok := productIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	resp, err := c.inventoryProxy.GetProduct(ctx.Request.Context(), productID)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"product": resp})
+}
+
+func (c *InventoryController) UpdateProduct(ctx *gin.Context) {
+	productID, ok := productIDParam(ctx)
+	if !ok {
+		return
+	}
+	var req model.UpdateProductReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.inventoryProxy.UpdateProduct(ctx.Request.Context(), productID, &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"product": resp})
+}
+
+func (c *InventoryController) AdjustStock(ctx *gin.Context) {
+	productID, ok := productIDParam(ctx)
+	if !ok {
+		return
+	}
+	var req model.AdjustStockReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.inventoryProxy.AdjustStock(ctx.Request.Context(), productID, &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"product": resp})
+}
+
+func (c *InventoryController) DeleteProduct(ctx *gin.Context) {
+	productID, ok := productIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.inventoryProxy.DeleteProduct(ctx.Request.Context(), productID); err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListStockMovements 按时间倒序返回产品的库存流水
+func (c *InventoryController) ListStockMovements(ctx *gin.Context) {
+	productID, ok := productIDParam(ctx)
+	if !ok {
+		return
+	}
+	var req model.ListStockMovementsReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.inventoryProxy.ListStockMovements(ctx.Request.Context(), productID, &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
 	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// maxImportFileSize 导入文件的最大字节数
+const maxImportFileSize = 10 << 20
 
+// ImportProducts 上传 CSV 或 JSONL 文件批量导入产品，请求体可以是 multipart 表单中的 file 字段或文件内容本身。
+// 返回逐行的处理结果，dry_run=true 时只返回将要发生的变化
+func (c *InventoryController) ImportProducts(ctx *gin.Context) {
+	var req model.ImportProductsReq
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(400, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	resp, err := c.inventoryProxy.GetAllInventory(ctx.Request.Context(), req.Offset, req.Limit)
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize)
+	body := io.Reader(ctx.Request.Body)
+	format := req.Format
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		header, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		body = file
+		if format == "" && strings.HasSuffix(strings.ToLower(header.Filename), ".jsonl") {
+			format = formatJSONL
+		}
+	}
+	if format == "" {
+		switch ctx.ContentType() {
+		case "application/jsonl", "application/x-ndjson":
+			format = formatJSONL
+		default:
+			format = formatCSV
+		}
+	}
+
+	rows, failures, err := parseProductFile(body, format)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(200, gin.H{
-		"inventory": resp,
+
+	resp := &model.ImportProductsResp{DryRun: req.DryRun}
+	if len(rows) > 0 {
+		resp, err = c.inventoryProxy.ImportProducts(ctx.Request.Context(), rows, req.DryRun, req.Actor)
+		if err != nil {
+			ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	// 无法解析的行与库存服务的处理结果合并为一份按行号排序的报告
+	resp.Failed += int64(len(failures))
+	resp.Results = append(resp.Results, failures...)
+	sort.SliceStable(resp.Results, func(i, j int) bool {
+		return resp.Results[i].Line < resp.Results[j].Line
 	})
+	if resp.Error != "" {
+		// 导入中途失败，已提交的行仍在报告中返回
+		ctx.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// ExportProducts 以 CSV 或 JSONL 下载全部产品，每个仓库一行，导出的文件可以直接重新导入
+func (c *InventoryController) ExportProducts(ctx *gin.Context) {
+	var req model.ExportProductsReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Format == "" {
+		req.Format = formatCSV
+	}
+
+	stream, err := c.inventoryProxy.ExportProducts(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	// 库存服务不可用等错误在收到首个产品前返回，此时仍可以返回普通的 HTTP 错误
+	product, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == formatJSONL {
+		contentType = "application/jsonl"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, req.Format))
+	ctx.Status(http.StatusOK)
+
+	writer, writerErr := newProductFileWriter(ctx.Writer, req.Format)
+	if writerErr != nil {
+		ctx.Error(writerErr)
+		return
+	}
+	for err == nil {
+		if err = writer.Write(product); err != nil {
+			break
+		}
+		product, err = stream.Recv()
+	}
+	if flushErr := writer.Flush(); err == io.EOF {
+		err = flushErr
+	}
+	if err != nil {
+		// 响应头已发送，只能中断下载并记录错误
+		ctx.Error(fmt.Errorf("failed to export products: %w", err))
+		ctx.Abort()
+	}
+}
+
+// productIDParam 解析路径中的产品ID，解析失败时直接返回 400
+func productIDParam(ctx *gin.Context) (int64, bool) {
+	productID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid product id"})
+		return 0, false
+	}
+	return productID, true
 }
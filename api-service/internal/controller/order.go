@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/gin-gonic/gin"
+	"io"
 	"net/http"
 	"order-microsystem/api-service/internal/domain/model"
 	"order-microsystem/api-service/internal/proxy"
@@ -25,11 +26,87 @@ func (c *OrderController) CreateOrder(ctx *gin.Context) {
 	}
 
 	stdCtx := ctx.Request.Context()
-	resp, err := c.orderProxy.CreateOrder(&stdCtx, &req)
+	resp, err := c.orderProxy.CreateOrder(&stdCtx, &req, ctx.GetHeader("Idempotency-Key"))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		code := httpStatusFromError(err)
+		// 同一个 Idempotency-Key 被用于不同的请求内容
+		if errorReason(err) == idempotencyKeyReusedReason {
+			code = http.StatusUnprocessableEntity
+		}
+		ctx.JSON(code, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, gin.H{"order": resp})
 }
+
+func (c *OrderController) ListOrders(ctx *gin.Context) {
+	var req model.ListOrdersReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.orderProxy.ListOrders(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *OrderController) CancelOrder(ctx *gin.Context) {
+	var req model.CancelOrderReq
+	// 取消原因为可选项，允许空请求体
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	resp, err := c.orderProxy.CancelOrder(ctx.Request.Context(), ctx.Param("id"), req.Reason)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"order": resp})
+}
+
+// WatchOrder 以 Server-Sent Events 推送订单状态变更，首个事件为订单当前状态
+func (c *OrderController) WatchOrder(ctx *gin.Context) {
+	stream, err := c.orderProxy.WatchOrder(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// 订单不存在等错误在收到首个事件前返回，此时仍可以返回普通的 HTTP 错误
+	event, err := stream.Recv()
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	for {
+		ctx.SSEvent("status", event)
+		ctx.Writer.Flush()
+
+		event, err = stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			if ctx.Request.Context().Err() == nil {
+				ctx.SSEvent("error", gin.H{"error": err.Error()})
+				ctx.Writer.Flush()
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"order-microsystem/api-service/internal/domain/model"
+	"order-microsystem/api-service/internal/proxy"
+)
+
+type PaymentController struct {
+	paymentProxy *proxy.PaymentProxy
+}
+
+func NewPaymentController(paymentProxy *proxy.PaymentProxy) *PaymentController {
+	return &PaymentController{
+		paymentProxy: paymentProxy,
+	}
+}
+
+// GetPayment 按支付ID查询支付记录
+func (c *PaymentController) GetPayment(ctx *gin.Context) {
+	payment, err := c.paymentProxy.GetPayment(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"payment": payment})
+}
+
+// GetOrderPayment 查询订单的支付记录
+func (c *PaymentController) GetOrderPayment(ctx *gin.Context) {
+	payment, err := c.paymentProxy.GetPaymentByOrder(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"payment": payment})
+}
+
+// ListPayments 按用户、状态与创建时间分页查询支付记录
+func (c *PaymentController) ListPayments(ctx *gin.Context) {
+	var req model.ListPaymentsReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.paymentProxy.ListPayments(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// RefundPayment 退回支付的部分或全部金额，仅供管理端使用。
+// 请求头 Idempotency-Key 用于安全重试，相同的键只会退款一次
+func (c *PaymentController) RefundPayment(ctx *gin.Context) {
+	var req model.RefundPaymentReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.paymentProxy.RefundPayment(ctx.Request.Context(), ctx.Param("id"), &req, ctx.GetHeader("Idempotency-Key"))
+	if err != nil {
+		ctx.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, resp)
+}
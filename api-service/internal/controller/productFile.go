@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"order-microsystem/api-service/internal/domain/model"
+	"strconv"
+	"strings"
+)
+
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+// productFileColumns 导入导出文件的列，导入时只有 product_id 列是必需的
+var productFileColumns = []string{"product_id", "product_name", "price", "quantity", "warehouse_id", "reorder_threshold"}
+
+// parseProductFile 解析导入文件，返回可以提交的行和无法解析的行。
+// 文件整体无法识别(如 CSV 表头错误)时返回 error
+func parseProductFile(r io.Reader, format string) ([]*model.ProductImportRow, []*model.ProductImportResult, error) {
+	if format == formatJSONL {
+		return parseProductJSONL(r)
+	}
+	return parseProductCSV(r)
+}
+
+func parseProductCSV(r io.Reader) ([]*model.ProductImportRow, []*model.ProductImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("empty file")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid csv header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if i == 0 {
+			// Excel 导出的 UTF-8 CSV 带有 BOM
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		if !isProductFileColumn(name) {
+			return nil, nil, fmt.Errorf("unknown column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, nil, fmt.Errorf("duplicate column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["product_id"]; !ok {
+		return nil, nil, errors.New("missing column \"product_id\"")
+	}
+
+	var rows []*model.ProductImportRow
+	var failures []*model.ProductImportResult
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				failures = append(failures, importFailure(int64(parseErr.StartLine), 0, parseErr.Err.Error()))
+				continue
+			}
+			return nil, nil, err
+		}
+		if len(record) != len(header) {
+			failures = append(failures, importFailure(int64(line), 0,
+				fmt.Sprintf("expected %d fields, got %d", len(header), len(record))))
+			continue
+		}
+
+		row, err := parseProductRecord(record, columns)
+		row.Line = int64(line)
+		if err != nil {
+			failures = append(failures, importFailure(row.Line, row.ProductID, err.Error()))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, failures, nil
+}
+
+// parseProductRecord 按列名解析 CSV 的一行，空单元格表示该字段保持不变
+func parseProductRecord(record []string, columns map[string]int) (*model.ProductImportRow, error) {
+	row := &model.ProductImportRow{}
+	value := func(column string) (string, bool) {
+		i, ok := columns[column]
+		if !ok {
+			return "", false
+		}
+		v := strings.TrimSpace(record[i])
+		return v, v != ""
+	}
+	optionalInt := func(column string) (*int64, error) {
+		v, ok := value(column)
+		if !ok {
+			return nil, nil
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", column, v)
+		}
+		return &n, nil
+	}
+
+	productID, err := optionalInt("product_id")
+	if err != nil {
+		return row, err
+	}
+	if productID == nil {
+		return row, errors.New("product_id is required")
+	}
+	row.ProductID = *productID
+	if name, ok := value("product_name"); ok {
+		row.ProductName = &name
+	}
+	if row.Price, err = optionalInt("price"); err != nil {
+		return row, err
+	}
+	if row.Quantity, err = optionalInt("quantity"); err != nil {
+		return row, err
+	}
+	if row.ReorderThreshold, err = optionalInt("reorder_threshold"); err != nil {
+		return row, err
+	}
+	row.WarehouseID, _ = value("warehouse_id")
+	return row, nil
+}
+
+func parseProductJSONL(r io.Reader) ([]*model.ProductImportRow, []*model.ProductImportResult, error) {
+	var rows []*model.ProductImportRow
+	var failures []*model.ProductImportResult
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var line int64
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := &model.ProductImportRow{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(row); err != nil {
+			failures = append(failures, importFailure(line, 0, fmt.Sprintf("invalid json: %v", err)))
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	if line == 0 {
+		return nil, nil, errors.New("empty file")
+	}
+	return rows, failures, nil
+}
+
+func isProductFileColumn(name string) bool {
+	for _, column := range productFileColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func importFailure(line int64, productID int64, reason string) *model.ProductImportResult {
+	return &model.ProductImportResult{
+		Line:      line,
+		ProductID: productID,
+		Action:    "failed",
+		Error:     reason,
+	}
+}
+
+// productFileWriter 将导出的产品按仓库逐行写入 CSV 或 JSONL，与导入文件的格式一致
+type productFileWriter struct {
+	format string
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+func newProductFileWriter(w io.Writer, format string) (*productFileWriter, error) {
+	writer := &productFileWriter{format: format}
+	if format == formatJSONL {
+		writer.json = json.NewEncoder(w)
+		return writer, nil
+	}
+	writer.csv = csv.NewWriter(w)
+	if err := writer.csv.Write(productFileColumns); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// Write 写入产品在各仓库的库存，每个仓库一行，没有仓库库存的产品只写一行产品信息
+func (w *productFileWriter) Write(product *model.Inventory) error {
+	stocks := product.Stocks
+	if len(stocks) == 0 {
+		stocks = []model.WarehouseStock{{}}
+	}
+	for _, stock := range stocks {
+		quantity := stock.Quantity
+		row := model.ProductImportRow{
+			ProductID:        product.ProductID,
+			ProductName:      &product.ProductName,
+			Price:            &product.Price,
+			Quantity:         &quantity,
+			WarehouseID:      stock.WarehouseID,
+			ReorderThreshold: &product.ReorderThreshold,
+		}
+		if err := w.writeRow(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *productFileWriter) writeRow(row *model.ProductImportRow) error {
+	if w.format == formatJSONL {
+		return w.json.Encode(row)
+	}
+	return w.csv.Write([]string{
+		strconv.FormatInt(row.ProductID, 10),
+		*row.ProductName,
+		strconv.FormatInt(*row.Price, 10),
+		strconv.FormatInt(*row.Quantity, 10),
+		row.WarehouseID,
+		strconv.FormatInt(*row.ReorderThreshold, 10),
+	})
+}
+
+// Flush 将缓冲的 CSV 写入底层的 writer
+func (w *productFileWriter) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
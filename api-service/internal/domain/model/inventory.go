@@ -2,7 +2,183 @@ package model
 
 type Inventory struct {
 	ProductID   int64  `json:"product_id"`
+	SKU         string `json:"sku"`
 	ProductName string `json:"product_name"`
 	Quantity    int64  `json:"quantity"`
+	Reserved    int64  `json:"reserved"`
+	Available   int64  `json:"available"`
 	Price       int64  `json:"price"`
+	// ReorderThreshold 补货阈值，为 0 表示不提醒
+	ReorderThreshold int64 `json:"reorder_threshold"`
+	// ParentProductID 所属父产品，为 0 表示单规格产品
+	ParentProductID uint64            `json:"parent_product_id,omitempty"`
+	CategoryID      uint64            `json:"category_id,omitempty"`
+	Attributes      map[string]string `json:"attributes,omitempty"`
+	// Stocks 各仓库的库存明细，仅在查询单个产品时返回
+	Stocks []WarehouseStock `json:"stocks,omitempty"`
+}
+
+type WarehouseStock struct {
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int64  `json:"quantity"`
+	Reserved    int64  `json:"reserved"`
+	Available   int64  `json:"available"`
+}
+
+// ListInventoryReq 库存列表的分页、过滤与排序参数，未提供的范围参数不限制
+type ListInventoryReq struct {
+	Offset       int32  `form:"offset"`
+	Limit        int32  `form:"limit"`
+	NameContains string `form:"name"`
+	MinPrice     *int64 `form:"min_price"`
+	MaxPrice     *int64 `form:"max_price"`
+	MinQuantity  *int64 `form:"min_quantity"`
+	MaxQuantity  *int64 `form:"max_quantity"`
+	// CategoryID 包含其下所有子分类中的产品
+	CategoryID      *uint64 `form:"category_id"`
+	ParentProductID *uint64 `form:"parent_product_id"`
+	// SortBy 可选 product_id、product_name、price、quantity、available
+	SortBy string `form:"sort_by"`
+	// SortOrder 可选 asc、desc，默认 asc
+	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+}
+
+type ListInventoryResp struct {
+	Inventory []*Inventory `json:"inventory"`
+	Total     int64        `json:"total"`
+}
+
+type CreateProductReq struct {
+	ProductID   int64  `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Price       int64  `json:"price"`
+	Quantity    int64  `json:"quantity"`
+	// WarehouseID 为空时使用默认仓库
+	WarehouseID      string `json:"warehouse_id"`
+	ReorderThreshold int64  `json:"reorder_threshold"`
+	// SKU 为空时以产品ID作为 SKU
+	SKU             string            `json:"sku"`
+	ParentProductID uint64            `json:"parent_product_id"`
+	CategoryID      uint64            `json:"category_id"`
+	Attributes      map[string]string `json:"attributes"`
+}
+
+// UpdateProductReq 中未提供的字段保持不变
+type UpdateProductReq struct {
+	ProductName      *string `json:"product_name"`
+	Price            *int64  `json:"price"`
+	ReorderThreshold *int64  `json:"reorder_threshold"`
+	// CategoryID 为 0 表示移出分类
+	CategoryID *uint64 `json:"category_id"`
+	// Attributes 提供时替换全部规格属性
+	Attributes *map[string]string `json:"attributes"`
+}
+
+type AdjustStockReq struct {
+	Delta  int64  `json:"delta"`
+	Reason string `json:"reason"`
+	Actor  string `json:"actor"`
+	// WarehouseID 为空时使用默认仓库
+	WarehouseID string `json:"warehouse_id"`
+}
+
+type StockMovement struct {
+	ID            uint64 `json:"id"`
+	ProductID     int64  `json:"product_id"`
+	WarehouseID   string `json:"warehouse_id"`
+	Delta         int64  `json:"delta"`
+	ReservedDelta int64  `json:"reserved_delta"`
+	Quantity      int64  `json:"quantity"`
+	Reserved      int64  `json:"reserved"`
+	Reason        string `json:"reason"`
+	Note          string `json:"note,omitempty"`
+	OrderID       string `json:"order_id,omitempty"`
+	Actor         string `json:"actor"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type ListStockMovementsReq struct {
+	OrderID   string `form:"order_id"`
+	PageSize  int32  `form:"page_size"`
+	PageToken string `form:"page_token"`
+}
+
+type ListStockMovementsResp struct {
+	Movements     []*StockMovement `json:"movements"`
+	NextPageToken string           `json:"next_page_token,omitempty"`
+}
+
+// ProductImportRow 导入文件中的一行，列与导出文件一致。未提供的字段保持不变，
+// Quantity 为产品在 WarehouseID 仓库的目标在库数量，WarehouseID 为空时使用默认仓库
+type ProductImportRow struct {
+	// Line 行在导入文件中的行号
+	Line             int64   `json:"-"`
+	ProductID        int64   `json:"product_id"`
+	ProductName      *string `json:"product_name,omitempty"`
+	Price            *int64  `json:"price,omitempty"`
+	Quantity         *int64  `json:"quantity,omitempty"`
+	WarehouseID      string  `json:"warehouse_id,omitempty"`
+	ReorderThreshold *int64  `json:"reorder_threshold,omitempty"`
+}
+
+type ImportProductsReq struct {
+	// Format 可选 csv、jsonl，为空时按上传文件的扩展名或 Content-Type 判断
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	// DryRun 为 true 时只返回将要发生的变化而不写入
+	DryRun bool   `form:"dry_run"`
+	Actor  string `form:"actor"`
+}
+
+type ProductImportResult struct {
+	Line      int64 `json:"line"`
+	ProductID int64 `json:"product_id"`
+	// Action 为 created、updated、unchanged、failed
+	Action  string   `json:"action"`
+	Changes []string `json:"changes,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+type ImportProductsResp struct {
+	DryRun    bool                   `json:"dry_run"`
+	Created   int64                  `json:"created"`
+	Updated   int64                  `json:"updated"`
+	Unchanged int64                  `json:"unchanged"`
+	Failed    int64                  `json:"failed"`
+	Results   []*ProductImportResult `json:"results"`
+	// Error 导入中途失败时的错误信息，Results 只包含失败前已处理的行
+	Error string `json:"error,omitempty"`
+}
+
+type ExportProductsReq struct {
+	// Format 可选 csv、jsonl，默认 csv
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+}
+
+type Category struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+	// ParentID 上级分类，为 0 表示顶级分类
+	ParentID uint64 `json:"parent_id"`
+}
+
+type CreateCategoryReq struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID uint64 `json:"parent_id"`
+}
+
+type ListCategoriesResp struct {
+	Categories []*Category `json:"categories"`
+}
+
+type ParentProduct struct {
+	ID          uint64 `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Variants 各规格的产品，仅在查询单个父产品时返回
+	Variants []*Inventory `json:"variants,omitempty"`
+}
+
+type CreateParentProductReq struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
 }
@@ -5,9 +5,13 @@ import (
 )
 
 type OrderItem struct {
-	ProductID int64 `json:"product_id"`
-	Quantity  int64 `json:"quantity"`
-	Price     int64 `json:"price"`
+	// 下单时可以只传入 SKU，订单中的产品ID以 SKU 对应的规格为准
+	ProductID int64  `json:"product_id"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int64  `json:"quantity"`
+	// 下单时无需传入单价，订单中的单价与名称以库存服务目录为准
+	Price       int64  `json:"price,omitempty"`
+	ProductName string `json:"product_name,omitempty"`
 }
 
 type Order struct {
@@ -18,6 +22,25 @@ type Order struct {
 	Status     string      `json:"status"`
 	CreatedAt  string      `json:"created_at"`
 	UpdatedAt  string      `json:"updated_at"`
+	ExpiresAt  string      `json:"expires_at,omitempty"`
+	Version    int64       `json:"version"`
+
+	StatusHistory []StatusChange `json:"status_history"`
+}
+
+type StatusChange struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Actor     string `json:"actor"`
+	Reason    string `json:"reason"`
+	ChangedAt string `json:"changed_at"`
+}
+
+// OrderEvent 是通过 SSE 推送给客户端的订单状态变更
+type OrderEvent struct {
+	OrderID string       `json:"order_id"`
+	Change  StatusChange `json:"change"`
+	Order   *Order       `json:"order"`
 }
 
 type CreateOrderReq struct {
@@ -28,3 +51,21 @@ type CreateOrderReq struct {
 type CreateOrderResp struct {
 	Order *Order `json:"order"`
 }
+
+type ListOrdersReq struct {
+	CustomerID    string `form:"customer_id"`
+	Status        string `form:"status"`
+	CreatedAfter  string `form:"created_after"`
+	CreatedBefore string `form:"created_before"`
+	PageSize      int32  `form:"page_size"`
+	PageToken     string `form:"page_token"`
+}
+
+type ListOrdersResp struct {
+	Orders        []*Order `json:"orders"`
+	NextPageToken string   `json:"next_page_token"`
+}
+
+type CancelOrderReq struct {
+	Reason string `json:"reason"`
+}
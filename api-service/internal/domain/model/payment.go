@@ -0,0 +1,57 @@
+package model
+
+type Payment struct {
+	PaymentID  string `json:"payment_id"`
+	UserID     string `json:"user_id"`
+	OrderID    string `json:"order_id"`
+	TotalPrice int64  `json:"total_price"`
+	// Status 为 pending、authorized、captured、failed、partially_refunded 或 refunded
+	Status         string `json:"status"`
+	FailureCode    string `json:"failure_code,omitempty"`
+	RefundedAmount int64  `json:"refunded_amount"`
+	Provider       string `json:"provider,omitempty"`
+	TransactionID  string `json:"transaction_id,omitempty"`
+
+	// 进入各状态的时间，尚未进入的状态省略
+	CreatedAt           string `json:"created_at"`
+	AuthorizedAt        string `json:"authorized_at,omitempty"`
+	CapturedAt          string `json:"captured_at,omitempty"`
+	FailedAt            string `json:"failed_at,omitempty"`
+	PartiallyRefundedAt string `json:"partially_refunded_at,omitempty"`
+	RefundedAt          string `json:"refunded_at,omitempty"`
+}
+
+type ListPaymentsReq struct {
+	UserID        string `form:"user_id"`
+	Status        string `form:"status"`
+	CreatedAfter  string `form:"created_after"`
+	CreatedBefore string `form:"created_before"`
+	PageSize      int32  `form:"page_size"`
+	PageToken     string `form:"page_token"`
+}
+
+type ListPaymentsResp struct {
+	Payments      []*Payment `json:"payments"`
+	NextPageToken string     `json:"next_page_token"`
+}
+
+type Refund struct {
+	RefundID  string `json:"refund_id"`
+	PaymentID string `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"created_at"`
+	// pending、succeeded、failed
+	Status string `json:"status"`
+}
+
+// RefundPaymentReq 退款金额可以小于支付金额，累计退款金额不能超过支付金额
+type RefundPaymentReq struct {
+	Amount int64  `json:"amount" binding:"required,gt=0"`
+	Reason string `json:"reason"`
+}
+
+type RefundPaymentResp struct {
+	Refund  *Refund  `json:"refund"`
+	Payment *Payment `json:"payment"`
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"github.com/afex/hystrix-go/hystrix"
 	"github.com/hashicorp/consul/api"
-	"github.com/pkg/errors"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
@@ -79,35 +78,445 @@ func NewInventoryProxy(cfg *config.Config) (*InventoryProxy, error) {
 	}, nil
 }
 
-func (p *InventoryProxy) GetAllInventory(ctx context.Context, offset int32, limit int32) ([]*model.Inventory, error) {
-	var inventories []*model.Inventory
+func (p *InventoryProxy) GetAllInventory(ctx context.Context, req *model.ListInventoryReq) (*model.ListInventoryResp, error) {
+	var result *model.ListInventoryResp
 
 	err := hystrix.Do("InventoryService", func() error {
 		resp, err := p.client.GetAllInventory(ctx, &pb.GetAllInventoryRequest{
-			Offset: offset,
-			Limit:  limit,
+			Offset:          req.Offset,
+			Limit:           req.Limit,
+			NameContains:    req.NameContains,
+			MinPrice:        req.MinPrice,
+			MaxPrice:        req.MaxPrice,
+			MinQuantity:     req.MinQuantity,
+			MaxQuantity:     req.MaxQuantity,
+			CategoryId:      req.CategoryID,
+			ParentProductId: req.ParentProductID,
+			SortBy:          req.SortBy,
+			Descending:      req.SortOrder == "desc",
 		})
 		if err != nil {
 			return err
 		}
 
+		inventories := make([]*model.Inventory, 0, len(resp.Products))
 		for _, item := range resp.Products {
-			inventories = append(inventories, &model.Inventory{
-				ProductID:   item.ProductId,
-				ProductName: item.ProductName,
-				Price:       item.Price,
-				Quantity:    item.Quantity,
+			inventories = append(inventories, convertToInventory(item))
+		}
+		result = &model.ListInventoryResp{
+			Inventory: inventories,
+			Total:     resp.Total,
+		}
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *InventoryProxy) CreateProduct(ctx context.Context, req *model.CreateProductReq) (*model.Inventory, error) {
+	var product *model.Inventory
+
+	err := hystrix.Do("CreateProduct", func() error {
+		resp, err := p.client.CreateProduct(ctx, &pb.CreateProductRequest{
+			ProductId:        req.ProductID,
+			ProductName:      req.ProductName,
+			Price:            req.Price,
+			Quantity:         req.Quantity,
+			WarehouseId:      req.WarehouseID,
+			ReorderThreshold: req.ReorderThreshold,
+			Sku:              req.SKU,
+			ParentProductId:  req.ParentProductID,
+			CategoryId:       req.CategoryID,
+			Attributes:       req.Attributes,
+		})
+		if err != nil {
+			return err
+		}
+		product = convertToInventory(resp.Product)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (p *InventoryProxy) GetProduct(ctx context.Context, productID int64) (*model.Inventory, error) {
+	var product *model.Inventory
+
+	err := hystrix.Do("GetProduct", func() error {
+		resp, err := p.client.GetProduct(ctx, &pb.GetProductRequest{ProductId: productID})
+		if err != nil {
+			return err
+		}
+		product = convertToInventory(resp.Product)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (p *InventoryProxy) UpdateProduct(ctx context.Context, productID int64, req *model.UpdateProductReq) (*model.Inventory, error) {
+	var product *model.Inventory
+
+	err := hystrix.Do("UpdateProduct", func() error {
+		updateReq := &pb.UpdateProductRequest{
+			ProductId:        productID,
+			ProductName:      req.ProductName,
+			Price:            req.Price,
+			ReorderThreshold: req.ReorderThreshold,
+			CategoryId:       req.CategoryID,
+		}
+		if req.Attributes != nil {
+			updateReq.Attributes = &pb.Attributes{Values: *req.Attributes}
+		}
+		resp, err := p.client.UpdateProduct(ctx, updateReq)
+		if err != nil {
+			return err
+		}
+		product = convertToInventory(resp.Product)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (p *InventoryProxy) AdjustStock(ctx context.Context, productID int64, req *model.AdjustStockReq) (*model.Inventory, error) {
+	var product *model.Inventory
+
+	err := hystrix.Do("AdjustStock", func() error {
+		resp, err := p.client.AdjustStock(ctx, &pb.AdjustStockRequest{
+			ProductId:   productID,
+			Delta:       req.Delta,
+			Reason:      req.Reason,
+			Actor:       req.Actor,
+			WarehouseId: req.WarehouseID,
+		})
+		if err != nil {
+			return err
+		}
+		product = convertToInventory(resp.Product)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (p *InventoryProxy) DeleteProduct(ctx context.Context, productID int64) error {
+	return hystrix.Do("DeleteProduct", func() error {
+		_, err := p.client.DeleteProduct(ctx, &pb.DeleteProductRequest{ProductId: productID})
+		return err
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+}
+
+func (p *InventoryProxy) ListStockMovements(ctx context.Context, productID int64, req *model.ListStockMovementsReq) (*model.ListStockMovementsResp, error) {
+	var result *model.ListStockMovementsResp
+
+	err := hystrix.Do("ListStockMovements", func() error {
+		resp, err := p.client.ListStockMovements(ctx, &pb.ListStockMovementsRequest{
+			ProductId: productID,
+			OrderId:   req.OrderID,
+			PageSize:  req.PageSize,
+			PageToken: req.PageToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		movements := make([]*model.StockMovement, 0, len(resp.Movements))
+		for _, movement := range resp.Movements {
+			movements = append(movements, &model.StockMovement{
+				ID:            movement.Id,
+				ProductID:     movement.ProductId,
+				WarehouseID:   movement.WarehouseId,
+				Delta:         movement.Delta,
+				ReservedDelta: movement.ReservedDelta,
+				Quantity:      movement.Quantity,
+				Reserved:      movement.Reserved,
+				Reason:        movement.Reason,
+				Note:          movement.Note,
+				OrderID:       movement.OrderId,
+				Actor:         movement.Actor,
+				CreatedAt:     movement.CreatedAt,
 			})
 		}
+		result = &model.ListStockMovementsResp{
+			Movements:     movements,
+			NextPageToken: resp.NextPageToken,
+		}
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
+	return result, nil
+}
+
+func (p *InventoryProxy) CreateCategory(ctx context.Context, req *model.CreateCategoryReq) (*model.Category, error) {
+	var category *model.Category
+
+	err := hystrix.Do("CreateCategory", func() error {
+		resp, err := p.client.CreateCategory(ctx, &pb.CreateCategoryRequest{
+			Name:     req.Name,
+			ParentId: req.ParentID,
+		})
+		if err != nil {
+			return err
+		}
+		category = convertToCategory(resp.Category)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (p *InventoryProxy) ListCategories(ctx context.Context) (*model.ListCategoriesResp, error) {
+	var result *model.ListCategoriesResp
+
+	err := hystrix.Do("ListCategories", func() error {
+		resp, err := p.client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+		if err != nil {
+			return err
+		}
+
+		categories := make([]*model.Category, 0, len(resp.Categories))
+		for _, category := range resp.Categories {
+			categories = append(categories, convertToCategory(category))
+		}
+		result = &model.ListCategoriesResp{Categories: categories}
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *InventoryProxy) DeleteCategory(ctx context.Context, categoryID uint64) error {
+	return hystrix.Do("DeleteCategory", func() error {
+		_, err := p.client.DeleteCategory(ctx, &pb.DeleteCategoryRequest{CategoryId: categoryID})
+		return err
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+}
+
+func (p *InventoryProxy) CreateParentProduct(ctx context.Context, req *model.CreateParentProductReq) (*model.ParentProduct, error) {
+	var parent *model.ParentProduct
+
+	err := hystrix.Do("CreateParentProduct", func() error {
+		resp, err := p.client.CreateParentProduct(ctx, &pb.CreateParentProductRequest{
+			Name:        req.Name,
+			Description: req.Description,
+		})
+		if err != nil {
+			return err
+		}
+		parent = convertToParentProduct(resp.ParentProduct)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parent, nil
+}
+
+func (p *InventoryProxy) GetParentProduct(ctx context.Context, parentProductID uint64) (*model.ParentProduct, error) {
+	var parent *model.ParentProduct
+
+	err := hystrix.Do("GetParentProduct", func() error {
+		resp, err := p.client.GetParentProduct(ctx, &pb.GetParentProductRequest{ParentProductId: parentProductID})
+		if err != nil {
+			return err
+		}
+		parent = convertToParentProduct(resp.ParentProduct)
 		return nil
 	}, func(err error) error {
-		return errors.Errorf("fallback triggered due to error: %v", err)
+		return fmt.Errorf("fallback triggered due to error: %w", err)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return inventories, nil
+	return parent, nil
+}
+
+// importChunkSize 导入时每条流消息携带的行数
+const importChunkSize = 500
+
+// ImportProducts 以客户端流分批上传导入行，返回库存服务的逐行处理结果。
+// 导入耗时与文件大小相关，不适合熔断器的超时控制，因此不经过 hystrix
+func (p *InventoryProxy) ImportProducts(ctx context.Context, rows []*model.ProductImportRow, dryRun bool, actor string) (*model.ImportProductsResp, error) {
+	stream, err := p.client.ImportProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for start := 0; start < len(rows); start += importChunkSize {
+		chunk := rows[start:min(start+importChunkSize, len(rows))]
+		req := &pb.ImportProductsRequest{
+			DryRun: dryRun,
+			Actor:  actor,
+			Rows:   make([]*pb.ProductImportRow, 0, len(chunk)),
+		}
+		for _, row := range chunk {
+			req.Rows = append(req.Rows, &pb.ProductImportRow{
+				Line:             row.Line,
+				ProductId:        row.ProductID,
+				ProductName:      row.ProductName,
+				Price:            row.Price,
+				Quantity:         row.Quantity,
+				ReorderThreshold: row.ReorderThreshold,
+				WarehouseId:      row.WarehouseID,
+			})
+		}
+		if err := stream.Send(req); err != nil {
+			// 服务端提前结束时 Send 返回 io.EOF，真正的错误由 CloseAndRecv 返回
+			break
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	result := &model.ImportProductsResp{
+		DryRun:    resp.DryRun,
+		Created:   resp.Created,
+		Updated:   resp.Updated,
+		Unchanged: resp.Unchanged,
+		Failed:    resp.Failed,
+		Results:   make([]*model.ProductImportResult, 0, len(resp.Results)),
+		Error:     resp.Error,
+	}
+	for _, item := range resp.Results {
+		result.Results = append(result.Results, &model.ProductImportResult{
+			Line:      item.Line,
+			ProductID: item.ProductId,
+			Action:    item.Action,
+			Changes:   item.Changes,
+			Error:     item.Error,
+		})
+	}
+	return result, nil
+}
+
+// ProductStream 读取库存服务导出的产品
+type ProductStream struct {
+	stream pb.InventoryService_ExportProductsClient
+}
+
+// Recv 返回下一个产品及其各仓库库存，导出结束时返回 io.EOF
+func (s *ProductStream) Recv() (*model.Inventory, error) {
+	product, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return convertToInventory(product), nil
+}
+
+// ExportProducts 按产品ID顺序导出全部产品，ctx 取消后导出结束。
+// 与 ImportProducts 相同，不经过 hystrix
+func (p *InventoryProxy) ExportProducts(ctx context.Context) (*ProductStream, error) {
+	stream, err := p.client.ExportProducts(ctx, &pb.ExportProductsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &ProductStream{stream: stream}, nil
+}
+
+// convertToInventory 将 proto 产品转换为网关的库存模型
+func convertToInventory(item *pb.Product) *model.Inventory {
+	var stocks []model.WarehouseStock
+	for _, stock := range item.Stocks {
+		stocks = append(stocks, model.WarehouseStock{
+			WarehouseID: stock.WarehouseId,
+			Quantity:    stock.Quantity,
+			Reserved:    stock.Reserved,
+			Available:   stock.Available,
+		})
+	}
+	return &model.Inventory{
+		ProductID:        item.ProductId,
+		SKU:              item.Sku,
+		ProductName:      item.ProductName,
+		Price:            item.Price,
+		Quantity:         item.Quantity,
+		Reserved:         item.Reserved,
+		Available:        item.Available,
+		Stocks:           stocks,
+		ReorderThreshold: item.ReorderThreshold,
+		ParentProductID:  item.ParentProductId,
+		CategoryID:       item.CategoryId,
+		Attributes:       item.Attributes,
+	}
+}
+
+func convertToCategory(category *pb.Category) *model.Category {
+	return &model.Category{
+		ID:       category.Id,
+		Name:     category.Name,
+		ParentID: category.ParentId,
+	}
+}
+
+func convertToParentProduct(parent *pb.ParentProduct) *model.ParentProduct {
+	variants := make([]*model.Inventory, 0, len(parent.Variants))
+	for _, variant := range parent.Variants {
+		variants = append(variants, convertToInventory(variant))
+	}
+	return &model.ParentProduct{
+		ID:          parent.Id,
+		Name:        parent.Name,
+		Description: parent.Description,
+		Variants:    variants,
+	}
 }
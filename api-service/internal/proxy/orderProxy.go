@@ -10,6 +10,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"order-microsystem/api-service/internal/domain/model"
 	"order-microsystem/api-service/pkg/config"
 	pb "order-microsystem/api-service/pkg/proto/order"
@@ -84,14 +85,21 @@ func NewOrderProxy(cfg *config.Config, logger *logrus.Logger) (*OrderProxy, erro
 	}, nil
 }
 
-func (p *OrderProxy) CreateOrder(ctx *context.Context, order *model.CreateOrderReq) (*model.Order, error) {
+// CreateOrder 创建订单，idempotencyKey 不为空时通过 gRPC 元数据透传给订单服务
+func (p *OrderProxy) CreateOrder(ctx *context.Context, order *model.CreateOrderReq, idempotencyKey string) (*model.Order, error) {
 	var respOrder *model.Order
 
+	callCtx := *ctx
+	if idempotencyKey != "" {
+		callCtx = metadata.AppendToOutgoingContext(callCtx, "idempotency-key", idempotencyKey)
+	}
+
 	err := hystrix.Do("CreateOrder", func() error {
 		var items []*pb.OrderItem
 		for _, item := range order.Items {
 			items = append(items, &pb.OrderItem{
 				ProductId: item.ProductID,
+				Sku:       item.SKU,
 				Quantity:  item.Quantity,
 				Price:     item.Price,
 			})
@@ -101,33 +109,78 @@ func (p *OrderProxy) CreateOrder(ctx *context.Context, order *model.CreateOrderR
 			Items:      items,
 		}
 
-		resp, err := p.client.CreateOrder(*ctx, req)
+		resp, err := p.client.CreateOrder(callCtx, req)
 		if err != nil {
 			p.logger.WithError(err).Errorf("failed to create order: %v", err)
-			return fmt.Errorf("failed to create order: %v", err)
+			return err
 		}
-		var orderItems []model.OrderItem
-		for _, item := range resp.Order.Items {
-			orderItems = append(orderItems, model.OrderItem{
-				ProductID: item.ProductId,
-				Quantity:  item.Quantity,
-				Price:     item.Price,
-			})
+		respOrder = convertToOrder(resp.Order)
+		return nil
+
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return respOrder, nil
+}
+
+func (p *OrderProxy) ListOrders(ctx context.Context, req *model.ListOrdersReq) (*model.ListOrdersResp, error) {
+	var result *model.ListOrdersResp
+
+	err := hystrix.Do("ListOrders", func() error {
+		resp, err := p.client.ListOrders(ctx, &pb.ListOrdersRequest{
+			CustomerId:    req.CustomerID,
+			Status:        req.Status,
+			CreatedAfter:  req.CreatedAfter,
+			CreatedBefore: req.CreatedBefore,
+			PageSize:      req.PageSize,
+			PageToken:     req.PageToken,
+		})
+		if err != nil {
+			p.logger.WithError(err).Errorf("failed to list orders: %v", err)
+			return err
 		}
 
-		respOrder = &model.Order{
-			ID:         resp.Order.Id,
-			CustomerID: resp.Order.CustomerId,
-			Items:      orderItems,
-			TotalPrice: resp.Order.TotalPrice,
-			Status:     resp.Order.Status,
-			CreatedAt:  resp.Order.CreatedAt,
-			UpdatedAt:  resp.Order.UpdatedAt,
+		orders := make([]*model.Order, 0, len(resp.Orders))
+		for _, order := range resp.Orders {
+			orders = append(orders, convertToOrder(order))
+		}
+		result = &model.ListOrdersResp{
+			Orders:        orders,
+			NextPageToken: resp.NextPageToken,
 		}
 		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
 
+func (p *OrderProxy) CancelOrder(ctx context.Context, id string, reason string) (*model.Order, error) {
+	var respOrder *model.Order
+
+	err := hystrix.Do("CancelOrder", func() error {
+		resp, err := p.client.CancelOrder(ctx, &pb.CancelOrderRequest{
+			Id:     id,
+			Reason: reason,
+		})
+		if err != nil {
+			p.logger.WithError(err).Errorf("failed to cancel order: %v", err)
+			return err
+		}
+		respOrder = convertToOrder(resp.Order)
+		return nil
 	}, func(err error) error {
-		return fmt.Errorf("fallback triggered due to error: %v", err)
+		return fmt.Errorf("fallback triggered due to error: %w", err)
 	})
 
 	if err != nil {
@@ -136,3 +189,74 @@ func (p *OrderProxy) CreateOrder(ctx *context.Context, order *model.CreateOrderR
 
 	return respOrder, nil
 }
+
+// OrderEventStream 读取订单服务推送的订单状态变更
+type OrderEventStream struct {
+	stream pb.OrderService_WatchOrderClient
+}
+
+// Recv 阻塞直到收到下一次状态变更，推送结束时返回 io.EOF
+func (s *OrderEventStream) Recv() (*model.OrderEvent, error) {
+	event, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return &model.OrderEvent{
+		OrderID: event.OrderId,
+		Change:  convertToStatusChange(event.Change),
+		Order:   convertToOrder(event.Order),
+	}, nil
+}
+
+// WatchOrder 订阅订单状态变更，ctx 取消后推送结束。
+// 长连接不适合熔断器的超时控制，因此不经过 hystrix
+func (p *OrderProxy) WatchOrder(ctx context.Context, id string) (*OrderEventStream, error) {
+	stream, err := p.client.WatchOrder(ctx, &pb.GetOrderRequest{Id: id})
+	if err != nil {
+		p.logger.WithError(err).Errorf("failed to watch order: %v", err)
+		return nil, err
+	}
+	return &OrderEventStream{stream: stream}, nil
+}
+
+// convertToOrder 将 proto 订单转换为网关的订单模型
+func convertToOrder(order *pb.Order) *model.Order {
+	orderItems := make([]model.OrderItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		orderItems = append(orderItems, model.OrderItem{
+			ProductID:   item.ProductId,
+			SKU:         item.Sku,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+			ProductName: item.ProductName,
+		})
+	}
+
+	history := make([]model.StatusChange, 0, len(order.StatusHistory))
+	for _, change := range order.StatusHistory {
+		history = append(history, convertToStatusChange(change))
+	}
+
+	return &model.Order{
+		ID:            order.Id,
+		CustomerID:    order.CustomerId,
+		Items:         orderItems,
+		TotalPrice:    order.TotalPrice,
+		Status:        order.Status,
+		CreatedAt:     order.CreatedAt,
+		UpdatedAt:     order.UpdatedAt,
+		ExpiresAt:     order.ExpiresAt,
+		Version:       order.Version,
+		StatusHistory: history,
+	}
+}
+
+func convertToStatusChange(change *pb.StatusChange) model.StatusChange {
+	return model.StatusChange{
+		From:      change.GetFrom(),
+		To:        change.GetTo(),
+		Actor:     change.GetActor(),
+		Reason:    change.GetReason(),
+		ChangedAt: change.GetChangedAt(),
+	}
+}
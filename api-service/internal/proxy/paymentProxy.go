@@ -0,0 +1,207 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"github.com/afex/hystrix-go/hystrix"
+	"github.com/hashicorp/consul/api"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"order-microsystem/api-service/internal/domain/model"
+	"order-microsystem/api-service/pkg/config"
+	pb "order-microsystem/api-service/pkg/proto/payment"
+	"time"
+)
+
+type PaymentProxy struct {
+	client pb.PaymentServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewPaymentProxy(cfg *config.Config) (*PaymentProxy, error) {
+	hystrix.ConfigureCommand("PaymentProxy", hystrix.CommandConfig{
+		Timeout:                cfg.Hystrix.Timeout,
+		MaxConcurrentRequests:  cfg.Hystrix.MaxConcurrentRequests,
+		RequestVolumeThreshold: cfg.Hystrix.RequestVolumeThreshold,
+		SleepWindow:            cfg.Hystrix.SleepWindow,
+		ErrorPercentThreshold:  cfg.Hystrix.ErrorPercentThreshold,
+	})
+
+	consulConfig := api.DefaultConfig()
+	consulConfig.Address = cfg.Consul.Address
+
+	var client *api.Client
+	var err error
+
+	// 添加重试逻辑
+	for i := 0; i < 5; i++ {
+		client, err = api.NewClient(consulConfig)
+		if err == nil {
+			break
+		}
+		time.Sleep(time.Second * time.Duration(i+1))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul client: %v", err)
+	}
+
+	services, _, err := client.Health().Service(cfg.Service.Payment.Name, "", true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service: %v", err)
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no healthy instances available")
+	}
+
+	service := services[0].Service
+	address := fmt.Sprintf("%s:%d", service.Address, service.Port)
+
+	conn, err := grpc.NewClient(
+		address,
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
+			otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
+		)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to grpc server: %v", err)
+	}
+
+	return &PaymentProxy{
+		client: pb.NewPaymentServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (p *PaymentProxy) GetPayment(ctx context.Context, paymentID string) (*model.Payment, error) {
+	var result *model.Payment
+
+	err := hystrix.Do("GetPayment", func() error {
+		resp, err := p.client.GetPayment(ctx, &pb.GetPaymentRequest{PaymentId: paymentID})
+		if err != nil {
+			return err
+		}
+		result = convertToPayment(resp.Payment)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *PaymentProxy) GetPaymentByOrder(ctx context.Context, orderID string) (*model.Payment, error) {
+	var result *model.Payment
+
+	err := hystrix.Do("GetPaymentByOrder", func() error {
+		resp, err := p.client.GetPaymentByOrder(ctx, &pb.GetPaymentByOrderRequest{OrderId: orderID})
+		if err != nil {
+			return err
+		}
+		result = convertToPayment(resp.Payment)
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *PaymentProxy) ListPayments(ctx context.Context, req *model.ListPaymentsReq) (*model.ListPaymentsResp, error) {
+	var result *model.ListPaymentsResp
+
+	err := hystrix.Do("ListPayments", func() error {
+		resp, err := p.client.GetAllPayment(ctx, &pb.GetAllPaymentRequest{
+			UserId:        req.UserID,
+			Status:        req.Status,
+			CreatedAfter:  req.CreatedAfter,
+			CreatedBefore: req.CreatedBefore,
+			PageSize:      req.PageSize,
+			PageToken:     req.PageToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		payments := make([]*model.Payment, 0, len(resp.Payments))
+		for _, payment := range resp.Payments {
+			payments = append(payments, convertToPayment(payment))
+		}
+		result = &model.ListPaymentsResp{
+			Payments:      payments,
+			NextPageToken: resp.NextPageToken,
+		}
+		return nil
+	}, func(err error) error {
+		return fmt.Errorf("fallback triggered due to error: %w", err)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RefundPayment 退款会调用支付渠道，不经过 hystrix 的超时控制，避免渠道已退款而网关提前返回超时。
+// idempotencyKey 不为空时通过 gRPC 元数据透传给支付服务，以相同的键重试不会重复退款
+func (p *PaymentProxy) RefundPayment(ctx context.Context, paymentID string, req *model.RefundPaymentReq, idempotencyKey string) (*model.RefundPaymentResp, error) {
+	if idempotencyKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "idempotency-key", idempotencyKey)
+	}
+	resp, err := p.client.RefundPayment(ctx, &pb.RefundPaymentRequest{
+		PaymentId: paymentID,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.RefundPaymentResp{
+		Refund: &model.Refund{
+			RefundID:  resp.Refund.RefundId,
+			PaymentID: resp.Refund.PaymentId,
+			Amount:    resp.Refund.Amount,
+			Reason:    resp.Refund.Reason,
+			CreatedAt: resp.Refund.CreatedAt,
+			Status:    resp.Refund.Status,
+		},
+		Payment: convertToPayment(resp.Payment),
+	}, nil
+}
+
+// convertToPayment 将 proto 支付记录转换为网关的支付模型
+func convertToPayment(payment *pb.Payment) *model.Payment {
+	return &model.Payment{
+		PaymentID:      payment.PaymentId,
+		UserID:         payment.UserId,
+		OrderID:        payment.OrderId,
+		TotalPrice:     payment.TotalPrice,
+		Status:         payment.Status,
+		FailureCode:    payment.FailureCode,
+		RefundedAmount: payment.RefundedAmount,
+		Provider:       payment.Provider,
+		TransactionID:  payment.TransactionId,
+
+		CreatedAt:           payment.CreatedAt,
+		AuthorizedAt:        payment.AuthorizedAt,
+		CapturedAt:          payment.CapturedAt,
+		FailedAt:            payment.FailedAt,
+		PartiallyRefundedAt: payment.PartiallyRefundedAt,
+		RefundedAt:          payment.RefundedAt,
+	}
+}
@@ -21,6 +21,7 @@ type HTTPServer struct {
 	config         *config.Config
 	orderProxy     *proxy.OrderProxy
 	inventoryProxy *proxy.InventoryProxy
+	paymentProxy   *proxy.PaymentProxy
 	tracer         *tracing.TracerProviderWrapper
 }
 
@@ -32,6 +33,10 @@ func NewHTTPServer(config *config.Config, tracer *tracing.TracerProviderWrapper,
 	if err != nil {
 		log.Fatalf("failed to create order proxy: %v", err)
 	}
+	paymentProxy, err := proxy.NewPaymentProxy(config)
+	if err != nil {
+		log.Fatalf("failed to create payment proxy: %v", err)
+	}
 
 	gin.SetMode(gin.ReleaseMode)
 	server := gin.New()
@@ -54,12 +59,14 @@ func NewHTTPServer(config *config.Config, tracer *tracing.TracerProviderWrapper,
 		tracer:         tracer,
 		orderProxy:     orderProxy,
 		inventoryProxy: inventoryProxy,
+		paymentProxy:   paymentProxy,
 	}
 }
 
 func (s *HTTPServer) Start() error {
 	orderController := controller.NewOrderController(s.orderProxy)
 	inventoryController := controller.NewInventoryController(s.inventoryProxy)
+	paymentController := controller.NewPaymentController(s.paymentProxy)
 
 	s.server.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -70,9 +77,35 @@ func (s *HTTPServer) Start() error {
 	api := s.server.Group("/api/v1")
 	{
 		api.POST("/order", orderController.CreateOrder)
+		api.GET("/orders", orderController.ListOrders)
+		api.POST("/orders/:id/cancel", orderController.CancelOrder)
+		api.GET("/orders/:id/events", orderController.WatchOrder)
 
 		api.GET("/inventory", inventoryController.GetAllInventory)
+		api.GET("/products/export", inventoryController.ExportProducts)
+		api.GET("/products/:id", inventoryController.GetProduct)
+		api.GET("/categories", inventoryController.ListCategories)
+		api.GET("/parent-products/:id", inventoryController.GetParentProduct)
 
+		// 管理端接口
+		admin := api.Group("/admin", AdminAuthMiddleware(s.config.Admin.Token))
+		admin.POST("/products", inventoryController.CreateProduct)
+		admin.POST("/products/import", inventoryController.ImportProducts)
+		admin.PUT("/products/:id", inventoryController.UpdateProduct)
+		admin.POST("/products/:id/stock-adjustments", inventoryController.AdjustStock)
+		admin.GET("/products/:id/stock-movements", inventoryController.ListStockMovements)
+		admin.DELETE("/products/:id", inventoryController.DeleteProduct)
+		admin.POST("/categories", inventoryController.CreateCategory)
+		admin.DELETE("/categories/:id", inventoryController.DeleteCategory)
+		admin.POST("/parent-products", inventoryController.CreateParentProduct)
+		admin.GET("/payments", paymentController.ListPayments)
+		admin.GET("/payments/:id", paymentController.GetPayment)
+		admin.GET("/orders/:id/payment", paymentController.GetOrderPayment)
+		admin.POST("/payments/:id/refunds", paymentController.RefundPayment)
+
+	}
+	if s.config.Admin.Token == "" {
+		log.Printf("admin token is not configured, admin api is disabled")
 	}
 	addr := fmt.Sprintf("%s:%d", s.config.Server.Host, s.config.Server.Port)
 	log.Printf("Starting HTTP Server on %s", addr)
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -61,6 +62,11 @@ type LoggerConfig struct {
 	Async        bool   `mapstructure:"async" yaml:"async"`
 }
 
+// AdminConfig 管理端接口配置，Token 为空时管理端接口不可用，可通过环境变量 ADMIN_TOKEN 设置
+type AdminConfig struct {
+	Token string `mapstructure:"token" yaml:"token"`
+}
+
 type Config struct {
 	Server  ServerConfig  `yaml:"server"`
 	Consul  ConsulConfig  `yaml:"consul"`
@@ -69,6 +75,7 @@ type Config struct {
 	Jaeger  JaegerConfig  `yaml:"jaeger"`
 	Hystrix HystrixConfig `yaml:"hystrix"`
 	Logger  LoggerConfig  `yaml:"logger"`
+	Admin   AdminConfig   `yaml:"admin"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -76,6 +83,7 @@ func LoadConfig(path string) (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err != nil {
@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"log"
+	"order-microsystem/inventory-service/internal/allocation"
 	"order-microsystem/inventory-service/internal/controller"
 	"order-microsystem/inventory-service/internal/domain/repository"
+	"order-microsystem/inventory-service/internal/scheduler"
 	"order-microsystem/inventory-service/internal/server"
 	"order-microsystem/inventory-service/internal/service"
+	"order-microsystem/inventory-service/pkg/cache"
 	"order-microsystem/inventory-service/pkg/config"
 	"order-microsystem/inventory-service/pkg/database"
 	"order-microsystem/inventory-service/pkg/messaging"
@@ -36,21 +39,62 @@ func main() {
 	// 创建 MySQL 仓库实例，用于操作数据库
 	repo := repository.NewMySQLRepository(db)
 	// 执行数据库自动迁移，创建或更新数据库表结构
-	if err = repo.AutoMigrations(); err != nil {
+	if err = repo.AutoMigrations(cfg.Inventory.DefaultWarehouse); err != nil {
 		// 若自动迁移失败，记录错误信息并终止程序
 		log.Fatalf("failed to call AutoMigration: %v", err)
 	}
 
+	// 按配置的仓库与分配策略创建仓库分配器
+	warehouses := make([]allocation.Warehouse, 0, len(cfg.Inventory.Warehouses))
+	for _, warehouse := range cfg.Inventory.Warehouses {
+		warehouses = append(warehouses, allocation.Warehouse{
+			ID:       warehouse.ID,
+			Region:   warehouse.Region,
+			Priority: warehouse.Priority,
+		})
+	}
+	strategy, err := allocation.NewStrategy(cfg.Inventory.Allocation.Strategy, warehouses, cfg.Inventory.Allocation.Region)
+	if err != nil {
+		log.Fatalf("failed to create allocation strategy: %v", err)
+	}
+
+	// 创建 Redis 客户端，用于秒杀库存的预扣
+	redisClient := cache.NewRedisClient(&cfg.Redis)
+
 	// 初始化 RabbitMQ 连接，传入 RabbitMQ 配置信息和数据库仓库实例
-	rabbitMQ, err := messaging.NewRabbitMQ(&cfg.RabbitMQ, repo)
+	rabbitMQ, err := messaging.NewRabbitMQ(&cfg.RabbitMQ, repo, cfg.Inventory.HoldTimeout, strategy, redisClient)
 	if err != nil {
 		// 若 RabbitMQ 连接失败，记录错误信息并终止程序
 		log.Fatalf("failed to connect RabbitMQ: %v", err)
 	}
 	// 延迟关闭 RabbitMQ 连接，在函数返回时执行
 	defer rabbitMQ.Close()
+	// 启动发件箱 relay，将已落库的领域事件投递到 RabbitMQ
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go messaging.NewOutboxRelay(rabbitMQ, repo).Run(relayCtx)
 	// 启动一个 goroutine 来消费 RabbitMQ 中订单创建的消息
 	go rabbitMQ.ConsumeOrderCreated()
+	// 启动一个 goroutine 来消费支付完成的消息，提交预留的库存
+	go rabbitMQ.ConsumePaymentCompleted()
+	// 启动一个 goroutine 来消费订单取消的消息，归还库存
+	go rabbitMQ.ConsumeOrderCancelled()
+	// 启动秒杀订单落库，将 Redis 中预扣成功的订单写入 MySQL
+	flashSaleCtx, stopFlashSale := context.WithCancel(context.Background())
+	defer stopFlashSale()
+	go rabbitMQ.PersistFlashSaleOrders(flashSaleCtx)
+
+	// 启动过期预留清理，归还超时仍未支付的库存预留
+	if cfg.Inventory.HoldTimeout > 0 {
+		sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+		defer stopSweeper()
+		go scheduler.NewHoldSweeper(repo, cfg.Inventory.HoldSweepInterval).Run(sweeperCtx)
+	}
+
+	// 定期刷新各产品的库存指标，供 Prometheus 告警规则判断库存是否低于补货阈值
+	metricsCtx, stopMetrics := context.WithCancel(context.Background())
+	defer stopMetrics()
+	go scheduler.NewStockMetrics(repo, cfg.Inventory.StockMetricsInterval).Run(metricsCtx)
 
 	// 初始化分布式追踪器，传入配置信息
 	tracerProvider, err := tracing.InitTracer(cfg)
@@ -66,9 +110,13 @@ func main() {
 	}()
 
 	// 创建库存服务实例，传入数据库仓库实例
-	inventoryService := service.NewInventoryService(repo)
+	inventoryService := service.NewInventoryService(repo, cfg.Inventory.WarehouseIDs(), cfg.Inventory.DefaultWarehouse)
+	// 创建产品目录服务实例，管理分类与多规格商品的父产品
+	catalogService := service.NewCatalogService(repo)
+	// 创建秒杀服务实例，传入数据库仓库实例和 Redis 客户端
+	flashSaleService := service.NewFlashSaleService(repo, redisClient)
 	// 创建库存控制器实例，传入库存服务实例
-	inventoryController := controller.NewInventoryController(inventoryService)
+	inventoryController := controller.NewInventoryController(inventoryService, catalogService, flashSaleService)
 
 	// 创建 gRPC 服务器实例，传入配置信息
 	grpcServer := server.NewGRPCServer(cfg)
@@ -0,0 +1,161 @@
+package allocation
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// StrategySingleWarehouseFirst 优先由一个仓库发出整单，没有仓库能满足整单时再拆分
+	StrategySingleWarehouseFirst = "single_warehouse_first"
+	// StrategyNearest 优先使用与配置区域相同的仓库，其余仓库按优先级补足
+	StrategyNearest = "nearest"
+	// StrategySplit 每个产品按仓库优先级依次占用库存，允许拆分到多个仓库
+	StrategySplit = "split"
+)
+
+// Warehouse 参与分配的仓库，Priority 越小越优先
+type Warehouse struct {
+	ID       string
+	Region   string
+	Priority int
+}
+
+// Item 订单中某个产品的需求数量
+type Item struct {
+	ProductID int64
+	Quantity  int64
+}
+
+// Allocation 从某个仓库为某个产品分配的数量
+type Allocation struct {
+	ProductID   int64  `json:"product_id"`
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int64  `json:"quantity"`
+}
+
+// Available 各产品在各仓库的可用库存，按 产品ID -> 仓库ID 索引
+type Available map[int64]map[string]int64
+
+// Strategy 为订单的各产品选择发货仓库。库存不足的产品分配到的数量会少于需求数量，
+// 调用方应据此判定整单库存不足
+type Strategy interface {
+	Allocate(items []Item, available Available) []Allocation
+}
+
+// NewStrategy 按名称创建分配策略，名称为空时使用 single_warehouse_first
+func NewStrategy(name string, warehouses []Warehouse, region string) (Strategy, error) {
+	ordered := make([]Warehouse, len(warehouses))
+	copy(ordered, warehouses)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority < ordered[j].Priority
+	})
+
+	switch name {
+	case StrategySingleWarehouseFirst, "":
+		return &singleWarehouseFirst{warehouses: ordered}, nil
+	case StrategyNearest:
+		if region == "" {
+			return nil, fmt.Errorf("strategy %s requires a region", name)
+		}
+		// 同区域的仓库排在前面，区域内外各自保持优先级顺序
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].Region == region && ordered[j].Region != region
+		})
+		return &nearest{warehouses: ordered}, nil
+	case StrategySplit:
+		return &split{warehouses: ordered}, nil
+	default:
+		return nil, fmt.Errorf("unknown allocation strategy: %s", name)
+	}
+}
+
+type singleWarehouseFirst struct {
+	warehouses []Warehouse
+}
+
+func (s *singleWarehouseFirst) Allocate(items []Item, available Available) []Allocation {
+	for _, warehouse := range s.warehouses {
+		if canFulfill(warehouse.ID, items, available) {
+			allocations := make([]Allocation, 0, len(items))
+			for _, item := range items {
+				allocations = append(allocations, Allocation{
+					ProductID:   item.ProductID,
+					WarehouseID: warehouse.ID,
+					Quantity:    item.Quantity,
+				})
+			}
+			return allocations
+		}
+	}
+	return splitAcross(s.warehouses, items, available)
+}
+
+type nearest struct {
+	warehouses []Warehouse
+}
+
+// Allocate 每个产品优先由最近的能满足全部数量的仓库发出，否则按远近拆分
+func (s *nearest) Allocate(items []Item, available Available) []Allocation {
+	var allocations []Allocation
+	for _, item := range items {
+		fulfilled := false
+		for _, warehouse := range s.warehouses {
+			if available[item.ProductID][warehouse.ID] >= item.Quantity {
+				allocations = append(allocations, Allocation{
+					ProductID:   item.ProductID,
+					WarehouseID: warehouse.ID,
+					Quantity:    item.Quantity,
+				})
+				fulfilled = true
+				break
+			}
+		}
+		if !fulfilled {
+			allocations = append(allocations, splitAcross(s.warehouses, []Item{item}, available)...)
+		}
+	}
+	return allocations
+}
+
+type split struct {
+	warehouses []Warehouse
+}
+
+func (s *split) Allocate(items []Item, available Available) []Allocation {
+	return splitAcross(s.warehouses, items, available)
+}
+
+// canFulfill 返回仓库能否满足全部产品的需求数量
+func canFulfill(warehouseID string, items []Item, available Available) bool {
+	for _, item := range items {
+		if available[item.ProductID][warehouseID] < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// splitAcross 按仓库顺序依次占用各产品的可用库存，直到满足需求数量或仓库用尽
+func splitAcross(warehouses []Warehouse, items []Item, available Available) []Allocation {
+	var allocations []Allocation
+	for _, item := range items {
+		remaining := item.Quantity
+		for _, warehouse := range warehouses {
+			if remaining == 0 {
+				break
+			}
+			quantity := min(remaining, available[item.ProductID][warehouse.ID])
+			if quantity <= 0 {
+				continue
+			}
+			allocations = append(allocations, Allocation{
+				ProductID:   item.ProductID,
+				WarehouseID: warehouse.ID,
+				Quantity:    quantity,
+			})
+			remaining -= quantity
+		}
+	}
+	return allocations
+}
@@ -3,19 +3,26 @@ package controller
 import (
 	"context"
 	"google.golang.org/grpc"
-	"log"
+	"io"
+	"order-microsystem/inventory-service/internal/domain/model"
 	"order-microsystem/inventory-service/internal/service"
 	pb "order-microsystem/inventory-service/pkg/proto/inventory"
+	"sort"
+	"time"
 )
 
 type InventoryController struct {
 	pb.UnimplementedInventoryServiceServer
-	svc *service.InventoryService
+	svc       *service.InventoryService
+	catalog   *service.CatalogService
+	flashSale *service.FlashSaleService
 }
 
-func NewInventoryController(svc *service.InventoryService) *InventoryController {
+func NewInventoryController(svc *service.InventoryService, catalog *service.CatalogService, flashSale *service.FlashSaleService) *InventoryController {
 	return &InventoryController{
-		svc: svc,
+		svc:       svc,
+		catalog:   catalog,
+		flashSale: flashSale,
 	}
 }
 
@@ -24,19 +31,397 @@ func RegisterInventoryController(server *grpc.Server, svc *InventoryController)
 }
 
 func (c *InventoryController) GetAllInventory(ctx context.Context, req *pb.GetAllInventoryRequest) (*pb.GetAllInventoryResponse, error) {
-	resp, err := c.svc.GetAllInventory(req.Offset, req.Limit)
+	filter := model.ProductFilter{
+		NameContains: req.NameContains,
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		MinQuantity:  req.MinQuantity,
+		MaxQuantity:  req.MaxQuantity,
+	}
+	if req.CategoryId != nil {
+		filter.CategoryIDs = []uint{uint(*req.CategoryId)}
+	}
+	if req.ParentProductId != nil {
+		parentProductID := uint(*req.ParentProductId)
+		filter.ParentProductID = &parentProductID
+	}
+	resp, total, err := c.svc.GetAllInventory(filter, req.SortBy, req.Descending, req.Offset, req.Limit)
 	if err != nil {
-		log.Fatalf("GetAllInventory failed: %v", err)
 		return nil, err
 	}
 	var products []*pb.Product
 	for _, item := range resp {
-		products = append(products, &pb.Product{
-			ProductId:   item.ProductID,
-			ProductName: item.ProductName,
-			Price:       item.Price,
-			Quantity:    item.Quantity,
+		products = append(products, convertToProto(item))
+	}
+	return &pb.GetAllInventoryResponse{Products: products, Total: total}, nil
+}
+
+func (c *InventoryController) GetOrderReservations(ctx context.Context, req *pb.GetOrderReservationsRequest) (*pb.GetOrderReservationsResponse, error) {
+	reservations, err := c.svc.GetOrderReservations(req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.Reservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		result = append(result, &pb.Reservation{
+			ProductId:   reservation.ProductID,
+			Quantity:    reservation.Quantity,
+			Status:      string(reservation.Status),
+			WarehouseId: reservation.WarehouseID,
+		})
+	}
+	return &pb.GetOrderReservationsResponse{Reservations: result}, nil
+}
+
+func (c *InventoryController) BatchGetProducts(ctx context.Context, req *pb.BatchGetProductsRequest) (*pb.BatchGetProductsResponse, error) {
+	products, err := c.svc.BatchGetProducts(req.ProductIds, req.Skus)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.Product, 0, len(products))
+	for _, item := range products {
+		result = append(result, convertToProto(item))
+	}
+	return &pb.BatchGetProductsResponse{Products: result}, nil
+}
+
+func (c *InventoryController) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.CreateProductResponse, error) {
+	product, err := c.svc.CreateProduct(&model.Product{
+		ProductID:        req.ProductId,
+		SKU:              req.Sku,
+		ProductName:      req.ProductName,
+		Price:            req.Price,
+		Quantity:         req.Quantity,
+		ReorderThreshold: req.ReorderThreshold,
+		ParentProductID:  optionalID(req.ParentProductId),
+		CategoryID:       optionalID(req.CategoryId),
+		Attributes:       convertToAttributes(req.Attributes),
+	}, req.WarehouseId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateProductResponse{Product: convertToProto(product)}, nil
+}
+
+func (c *InventoryController) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.GetProductResponse, error) {
+	product, err := c.svc.GetProduct(req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetProductResponse{Product: convertToProto(product)}, nil
+}
+
+func (c *InventoryController) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.UpdateProductResponse, error) {
+	var categoryID *uint
+	if req.CategoryId != nil {
+		id := uint(*req.CategoryId)
+		categoryID = &id
+	}
+	var attributes []*model.ProductAttribute
+	if req.Attributes != nil {
+		attributes = convertToAttributes(req.Attributes.Values)
+	}
+	product, err := c.svc.UpdateProduct(req.ProductId, req.ProductName, req.Price, req.ReorderThreshold, categoryID, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UpdateProductResponse{Product: convertToProto(product)}, nil
+}
+
+func (c *InventoryController) AdjustStock(ctx context.Context, req *pb.AdjustStockRequest) (*pb.AdjustStockResponse, error) {
+	product, err := c.svc.AdjustStock(req.ProductId, req.WarehouseId, req.Delta, req.Reason, req.Actor)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AdjustStockResponse{Product: convertToProto(product)}, nil
+}
+
+func (c *InventoryController) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*pb.DeleteProductResponse, error) {
+	if err := c.svc.DeleteProduct(req.ProductId); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteProductResponse{}, nil
+}
+
+func (c *InventoryController) ListStockMovements(ctx context.Context, req *pb.ListStockMovementsRequest) (*pb.ListStockMovementsResponse, error) {
+	movements, nextPageToken, err := c.svc.ListStockMovements(req.ProductId, req.OrderId, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.StockMovement, 0, len(movements))
+	for _, movement := range movements {
+		result = append(result, &pb.StockMovement{
+			Id:            uint64(movement.ID),
+			ProductId:     movement.ProductID,
+			WarehouseId:   movement.WarehouseID,
+			Delta:         movement.Delta,
+			ReservedDelta: movement.ReservedDelta,
+			Quantity:      movement.Quantity,
+			Reserved:      movement.Reserved,
+			Reason:        string(movement.Reason),
+			Note:          movement.Note,
+			OrderId:       movement.OrderID,
+			Actor:         movement.Actor,
+			CreatedAt:     movement.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return &pb.ListStockMovementsResponse{Movements: result, NextPageToken: nextPageToken}, nil
+}
+
+func (c *InventoryController) CheckStockConsistency(ctx context.Context, req *pb.CheckStockConsistencyRequest) (*pb.CheckStockConsistencyResponse, error) {
+	discrepancies, err := c.svc.CheckStockConsistency()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.StockDiscrepancy, 0, len(discrepancies))
+	for _, discrepancy := range discrepancies {
+		result = append(result, &pb.StockDiscrepancy{
+			ProductId:      discrepancy.ProductID,
+			Quantity:       discrepancy.Quantity,
+			LedgerQuantity: discrepancy.LedgerQuantity,
+			Reserved:       discrepancy.Reserved,
+			LedgerReserved: discrepancy.LedgerReserved,
+		})
+	}
+	return &pb.CheckStockConsistencyResponse{Discrepancies: result}, nil
+}
+
+func (c *InventoryController) ImportProducts(stream pb.InventoryService_ImportProductsServer) error {
+	var rows []*model.ProductImportRow
+	var dryRun bool
+	var actor string
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			dryRun = req.DryRun
+			actor = req.Actor
+		}
+		for _, row := range req.Rows {
+			rows = append(rows, &model.ProductImportRow{
+				Line:             row.Line,
+				ProductID:        row.ProductId,
+				ProductName:      row.ProductName,
+				Price:            row.Price,
+				Quantity:         row.Quantity,
+				ReorderThreshold: row.ReorderThreshold,
+				WarehouseID:      row.WarehouseId,
+			})
+		}
+	}
+
+	results, err := c.svc.ImportProducts(rows, dryRun, actor)
+	if err != nil && results == nil {
+		return err
+	}
+	resp := &pb.ImportProductsResponse{
+		DryRun:  dryRun,
+		Results: make([]*pb.ProductImportResult, 0, len(results)),
+	}
+	if err != nil {
+		// 部分批次已提交，返回已处理行的结果而不是丢弃
+		resp.Error = err.Error()
+	}
+	for _, result := range results {
+		switch result.Action {
+		case model.ProductImportCreated:
+			resp.Created++
+		case model.ProductImportUpdated:
+			resp.Updated++
+		case model.ProductImportUnchanged:
+			resp.Unchanged++
+		case model.ProductImportFailed:
+			resp.Failed++
+		}
+		resp.Results = append(resp.Results, &pb.ProductImportResult{
+			Line:      result.Line,
+			ProductId: result.ProductID,
+			Action:    string(result.Action),
+			Changes:   result.Changes,
+			Error:     result.Error,
 		})
 	}
-	return &pb.GetAllInventoryResponse{Products: products}, nil
+	return stream.SendAndClose(resp)
+}
+
+func (c *InventoryController) ExportProducts(req *pb.ExportProductsRequest, stream pb.InventoryService_ExportProductsServer) error {
+	return c.svc.ExportProducts(func(product *model.Product) error {
+		return stream.Send(convertToProto(product))
+	})
+}
+
+func (c *InventoryController) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CreateCategoryResponse, error) {
+	category, err := c.catalog.CreateCategory(req.Name, optionalID(req.ParentId))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateCategoryResponse{Category: convertCategoryToProto(category)}, nil
+}
+
+func (c *InventoryController) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	categories, err := c.catalog.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*pb.Category, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, convertCategoryToProto(category))
+	}
+	return &pb.ListCategoriesResponse{Categories: result}, nil
+}
+
+func (c *InventoryController) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error) {
+	if err := c.catalog.DeleteCategory(uint(req.CategoryId)); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteCategoryResponse{}, nil
+}
+
+func (c *InventoryController) CreateParentProduct(ctx context.Context, req *pb.CreateParentProductRequest) (*pb.CreateParentProductResponse, error) {
+	parent, err := c.catalog.CreateParentProduct(req.Name, req.Description)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateParentProductResponse{ParentProduct: convertParentProductToProto(parent)}, nil
+}
+
+func (c *InventoryController) GetParentProduct(ctx context.Context, req *pb.GetParentProductRequest) (*pb.GetParentProductResponse, error) {
+	parent, err := c.catalog.GetParentProduct(uint(req.ParentProductId))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetParentProductResponse{ParentProduct: convertParentProductToProto(parent)}, nil
+}
+
+func (c *InventoryController) StartFlashSale(ctx context.Context, req *pb.StartFlashSaleRequest) (*pb.StartFlashSaleResponse, error) {
+	sale, err := c.flashSale.StartFlashSale(req.ProductId, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StartFlashSaleResponse{Sale: convertFlashSaleToProto(sale)}, nil
+}
+
+func (c *InventoryController) StopFlashSale(ctx context.Context, req *pb.StopFlashSaleRequest) (*pb.StopFlashSaleResponse, error) {
+	sale, err := c.flashSale.StopFlashSale(req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StopFlashSaleResponse{Sale: convertFlashSaleToProto(sale)}, nil
+}
+
+func (c *InventoryController) ReconcileFlashSale(ctx context.Context, req *pb.ReconcileFlashSaleRequest) (*pb.ReconcileFlashSaleResponse, error) {
+	sale, backlog, consistent, err := c.flashSale.ReconcileFlashSale(req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReconcileFlashSaleResponse{
+		Sale:       convertFlashSaleToProto(sale),
+		Backlog:    backlog,
+		Consistent: consistent,
+	}, nil
+}
+
+// convertToProto 将产品模型转换为 proto 产品，已加载仓库库存明细时一并转换
+func convertToProto(product *model.Product) *pb.Product {
+	stocks := make([]*pb.WarehouseStock, 0, len(product.Stocks))
+	for _, stock := range product.Stocks {
+		stocks = append(stocks, &pb.WarehouseStock{
+			WarehouseId: stock.WarehouseID,
+			Quantity:    stock.Quantity,
+			Reserved:    stock.Reserved,
+			Available:   stock.Available(),
+		})
+	}
+	return &pb.Product{
+		ProductId:        product.ProductID,
+		ProductName:      product.ProductName,
+		Price:            product.Price,
+		Quantity:         product.Quantity,
+		Reserved:         product.Reserved,
+		Available:        product.Available(),
+		Stocks:           stocks,
+		ReorderThreshold: product.ReorderThreshold,
+		Sku:              product.SKU,
+		ParentProductId:  protoID(product.ParentProductID),
+		CategoryId:       protoID(product.CategoryID),
+		Attributes:       product.AttributeMap(),
+	}
+}
+
+func convertCategoryToProto(category *model.Category) *pb.Category {
+	return &pb.Category{
+		Id:       uint64(category.ID),
+		Name:     category.Name,
+		ParentId: protoID(category.ParentID),
+	}
+}
+
+func convertParentProductToProto(parent *model.ParentProduct) *pb.ParentProduct {
+	variants := make([]*pb.Product, 0, len(parent.Variants))
+	for _, variant := range parent.Variants {
+		variants = append(variants, convertToProto(variant))
+	}
+	return &pb.ParentProduct{
+		Id:          uint64(parent.ID),
+		Name:        parent.Name,
+		Description: parent.Description,
+		Variants:    variants,
+	}
+}
+
+// convertToAttributes 将 proto 中的规格属性转换为按属性名排序的模型
+func convertToAttributes(values map[string]string) []*model.ProductAttribute {
+	attributes := make([]*model.ProductAttribute, 0, len(values))
+	for key, value := range values {
+		attributes = append(attributes, &model.ProductAttribute{Key: key, Value: value})
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+	return attributes
+}
+
+// optionalID 将 proto 中以 0 表示未设置的ID转换为可为空的ID
+func optionalID(id uint64) *uint {
+	if id == 0 {
+		return nil
+	}
+	value := uint(id)
+	return &value
+}
+
+// protoID 将可为空的ID转换为 proto 中以 0 表示未设置的ID
+func protoID(id *uint) uint64 {
+	if id == nil {
+		return 0
+	}
+	return uint64(*id)
+}
+
+func convertFlashSaleToProto(sale *model.FlashSale) *pb.FlashSale {
+	result := &pb.FlashSale{
+		ProductId: sale.ProductID,
+		Quantity:  sale.Quantity,
+		Remaining: sale.Remaining,
+		Sold:      sale.Sold,
+		Persisted: sale.Persisted,
+		Status:    string(sale.Status),
+		StartedAt: sale.CreatedAt.Format(time.RFC3339),
+	}
+	if sale.StoppedAt != nil {
+		result.StoppedAt = sale.StoppedAt.Format(time.RFC3339)
+	}
+	if sale.ReconciledAt != nil {
+		result.ReconciledAt = sale.ReconciledAt.Format(time.RFC3339)
+	}
+	return result
 }
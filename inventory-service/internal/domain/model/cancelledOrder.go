@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// CancelledOrder 记录已取消的订单。取消事件可能先于 order.created 到达，
+// 预留库存前检查该记录，避免为已取消的订单预留的库存一直占用到预留过期
+type CancelledOrder struct {
+	OrderID     string    `gorm:"type:varchar(64);primaryKey;comment:订单ID"`
+	CancelledAt time.Time `gorm:"index:idx_cancelled;comment:取消时间"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"strconv"
+)
+
+// Category 产品分类，通过 ParentID 组成分类树，ParentID 为 nil 的是顶级分类
+type Category struct {
+	gorm.Model
+	Name     string `gorm:"type:varchar(60);not null;comment:分类名"`
+	ParentID *uint  `gorm:"index:idx_category_parent;comment:上级分类ID"`
+}
+
+// ParentProduct 多规格商品的父产品，各规格(如尺码、颜色)作为变体 Product 各自拥有 SKU、单价和库存
+type ParentProduct struct {
+	gorm.Model
+	Name        string `gorm:"type:varchar(60);not null;comment:商品名"`
+	Description string `gorm:"type:varchar(255);not null;default:'';comment:商品描述"`
+	// Variants 各规格的产品，仅在查询单个父产品时加载
+	Variants []*Product `gorm:"-"`
+}
+
+// ProductAttribute 产品的规格属性，如 color=red、size=XL，同一产品的属性名不重复
+type ProductAttribute struct {
+	ID        uint   `gorm:"primaryKey"`
+	ProductID int64  `gorm:"type:bigint;not null;comment:产品ID;uniqueIndex:idx_product_attribute"`
+	Key       string `gorm:"type:varchar(32);not null;comment:属性名;uniqueIndex:idx_product_attribute"`
+	Value     string `gorm:"type:varchar(64);not null;comment:属性值"`
+}
+
+// DefaultSKU 未指定 SKU 的产品以产品ID作为 SKU
+func DefaultSKU(productID int64) string {
+	return strconv.FormatInt(productID, 10)
+}
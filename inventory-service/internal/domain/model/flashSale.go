@@ -0,0 +1,34 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type FlashSaleStatus string
+
+const (
+	// FlashSaleStatusActive 秒杀进行中，产品的订单先在 Redis 中预扣库存
+	FlashSaleStatusActive FlashSaleStatus = "active"
+	// FlashSaleStatusStopped 秒杀已停止，等待预扣的订单全部落库后对账
+	FlashSaleStatusStopped FlashSaleStatus = "stopped"
+	// FlashSaleStatusReconciled Redis 与 MySQL 的秒杀数量已核对一致
+	FlashSaleStatusReconciled FlashSaleStatus = "reconciled"
+)
+
+// FlashSale 记录一次产品秒杀，同一产品同时只能有一次未对账的秒杀
+type FlashSale struct {
+	gorm.Model
+	ProductID int64 `gorm:"type:bigint;not null;comment:产品ID;index:idx_flash_sale_product"`
+	// Quantity 预热到 Redis 的秒杀库存
+	Quantity int64 `gorm:"type:bigint;not null;comment:预热数量"`
+	// Remaining 停止时 Redis 中剩余的秒杀库存
+	Remaining int64 `gorm:"type:bigint;not null;default:0;comment:剩余数量"`
+	// Sold Redis 中记录的已售数量，停止和对账时更新
+	Sold int64 `gorm:"type:bigint;not null;default:0;comment:已售数量"`
+	// Persisted 已落库的秒杀订单数量，与订单的库存预留在同一事务中累加
+	Persisted    int64           `gorm:"type:bigint;not null;default:0;comment:已落库数量"`
+	Status       FlashSaleStatus `gorm:"type:varchar(16);not null;comment:状态"`
+	StoppedAt    *time.Time      `gorm:"comment:停止时间"`
+	ReconciledAt *time.Time      `gorm:"comment:对账时间"`
+}
@@ -7,5 +7,55 @@ type Product struct {
 	ProductID   int64  `gorm:"type:bigint;not null;comment:产品ID;uniqueIndex:idx_product"`
 	ProductName string `gorm:"type:varchar(60);not null;comment:产品名"`
 	Price       int64  `gorm:"type:bigint;not null;comment:产品单价"`
-	Quantity    int64  `gorm:"type:bigint;comment:产品数量"`
+	// Quantity 为在库总数，其中 Reserved 部分已被未支付的订单预留
+	Quantity int64 `gorm:"type:bigint;comment:产品数量"`
+	Reserved int64 `gorm:"type:bigint;not null;default:0;comment:已预留数量"`
+	// ReorderThreshold 补货阈值，预留使可用库存低于该值时发布 inventory.low，为 0 表示不提醒
+	ReorderThreshold int64 `gorm:"type:bigint;not null;default:0;comment:补货阈值"`
+	// SKU 产品的库存单位编码，订单按 SKU 引用产品。列可为空以便为已有产品补全
+	SKU string `gorm:"type:varchar(64);default:null;comment:SKU;uniqueIndex:idx_product_sku"`
+	// ParentProductID 多规格商品的父产品ID，为 nil 表示单规格产品
+	ParentProductID *uint `gorm:"index:idx_product_parent;comment:父产品ID"`
+	CategoryID      *uint `gorm:"index:idx_product_category;comment:分类ID"`
+	// Stocks 各仓库的库存明细，仅在查询单个产品和导出时加载
+	Stocks []*WarehouseStock `gorm:"-"`
+	// Attributes 规格属性，查询产品时加载
+	Attributes []*ProductAttribute `gorm:"-"`
+}
+
+// Available 返回可供新订单预留的库存数量
+func (p *Product) Available() int64 {
+	return p.Quantity - p.Reserved
+}
+
+// BelowThreshold 返回可用库存是否低于补货阈值
+func (p *Product) BelowThreshold() bool {
+	return p.ReorderThreshold > 0 && p.Available() < p.ReorderThreshold
+}
+
+// ProductFilter 产品列表的过滤条件，零值字段不参与过滤
+type ProductFilter struct {
+	NameContains string
+	MinPrice     *int64
+	MaxPrice     *int64
+	MinQuantity  *int64
+	MaxQuantity  *int64
+	// CategoryIDs 产品所属分类须在其中，为 nil 时不按分类过滤
+	CategoryIDs     []uint
+	ParentProductID *uint
+}
+
+// ProductSort 产品列表的排序方式，Column 须为已校验的列名或表达式
+type ProductSort struct {
+	Column     string
+	Descending bool
+}
+
+// AttributeMap 以属性名为键返回产品的规格属性
+func (p *Product) AttributeMap() map[string]string {
+	attributes := make(map[string]string, len(p.Attributes))
+	for _, attribute := range p.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	return attributes
 }
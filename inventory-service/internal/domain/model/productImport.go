@@ -0,0 +1,37 @@
+package model
+
+// ProductImportAction 导入行的处理结果
+type ProductImportAction string
+
+const (
+	// ProductImportCreated 新建了产品
+	ProductImportCreated ProductImportAction = "created"
+	// ProductImportUpdated 更新了产品信息或仓库库存
+	ProductImportUpdated ProductImportAction = "updated"
+	// ProductImportUnchanged 行内容与现有产品一致
+	ProductImportUnchanged ProductImportAction = "unchanged"
+	// ProductImportFailed 行未通过校验，未做任何修改
+	ProductImportFailed ProductImportAction = "failed"
+)
+
+// ProductImportRow 批量导入中的一行，按 ProductID 新建或更新产品，为 nil 的字段保持不变。
+// Quantity 为产品在 WarehouseID 仓库的目标在库数量，而非增量，重复导入同一文件不会重复入库
+type ProductImportRow struct {
+	// Line 行在导入文件中的行号，用于错误报告
+	Line             int64
+	ProductID        int64
+	ProductName      *string
+	Price            *int64
+	Quantity         *int64
+	ReorderThreshold *int64
+	WarehouseID      string
+}
+
+// ProductImportResult 导入行的处理结果，Changes 描述了各字段的变化
+type ProductImportResult struct {
+	Line      int64
+	ProductID int64
+	Action    ProductImportAction
+	Changes   []string
+	Error     string
+}
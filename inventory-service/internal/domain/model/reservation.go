@@ -0,0 +1,32 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type ReservationStatus string
+
+const (
+	// ReservationStatusLocked 库存已为订单预留，等待支付
+	ReservationStatusLocked ReservationStatus = "locked"
+	// ReservationStatusCommitted 订单已支付，预留的库存已从在库总数中扣除
+	ReservationStatusCommitted ReservationStatus = "committed"
+	// ReservationStatusReleased 预留或已扣除的库存已归还
+	ReservationStatusReleased ReservationStatus = "released"
+	// ReservationStatusRejected 库存不足导致整单被拒绝，未扣减任何库存，仅用于识别重复投递的订单
+	ReservationStatusRejected ReservationStatus = "rejected"
+)
+
+// Reservation 记录某个订单对某个产品的库存预留，支付完成时提交，取消或超时时归还
+type Reservation struct {
+	gorm.Model
+	OrderID   string `gorm:"type:varchar(64);not null;comment:订单ID;index:idx_order"`
+	ProductID int64  `gorm:"type:bigint;not null;comment:产品ID"`
+	// WarehouseID 发货仓库，被拒绝的预留没有仓库
+	WarehouseID string            `gorm:"type:varchar(32);not null;default:'';comment:仓库ID"`
+	Quantity    int64             `gorm:"type:bigint;not null;comment:扣减数量"`
+	Status      ReservationStatus `gorm:"type:varchar(16);not null;comment:状态;index:idx_status_expires"`
+	// ExpiresAt 预留的过期时间，过期仍未支付的预留会被自动归还
+	ExpiresAt *time.Time `gorm:"comment:预留过期时间;index:idx_status_expires"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+type MovementReason string
+
+const (
+	// MovementReasonInitial 产品创建时的期初库存
+	MovementReasonInitial MovementReason = "initial"
+	// MovementReasonReserve 为订单预留库存
+	MovementReasonReserve MovementReason = "reserve"
+	// MovementReasonCommit 订单支付完成，预留的库存从在库总数中扣除
+	MovementReasonCommit MovementReason = "commit"
+	// MovementReasonRelease 订单取消或预留超时，归还预留或已扣除的库存
+	MovementReasonRelease MovementReason = "release"
+	// MovementReasonAdjust 人工调减库存，如盘亏、报损
+	MovementReasonAdjust MovementReason = "adjust"
+	// MovementReasonRestock 人工补货入库
+	MovementReasonRestock MovementReason = "restock"
+)
+
+const (
+	// MovementActorSystem 由订单、支付事件驱动的库存变动
+	MovementActorSystem = "system"
+	// MovementActorHoldSweeper 由过期预留清理任务触发的库存变动
+	MovementActorHoldSweeper = "hold-sweeper"
+	// MovementActorAdmin 未指定操作人时的人工操作
+	MovementActorAdmin = "admin"
+)
+
+// StockMovement 库存流水，只追加不修改，与对应的库存变更在同一事务中写入。
+// 每个产品全部流水的 Delta 之和等于其在库总数，ReservedDelta 之和等于其预留数量；
+// Quantity、Reserved 为变动后该仓库的库存
+type StockMovement struct {
+	ID            uint           `gorm:"primaryKey"`
+	ProductID     int64          `gorm:"type:bigint;not null;comment:产品ID;index:idx_product_movement"`
+	WarehouseID   string         `gorm:"type:varchar(32);not null;default:'';comment:仓库ID"`
+	Delta         int64          `gorm:"type:bigint;not null;comment:在库数量变化"`
+	ReservedDelta int64          `gorm:"type:bigint;not null;default:0;comment:预留数量变化"`
+	Quantity      int64          `gorm:"type:bigint;not null;comment:变动后在库数量"`
+	Reserved      int64          `gorm:"type:bigint;not null;comment:变动后预留数量"`
+	Reason        MovementReason `gorm:"type:varchar(16);not null;comment:变动原因"`
+	Note          string         `gorm:"type:varchar(255);comment:备注"`
+	OrderID       string         `gorm:"type:varchar(64);comment:订单ID;index:idx_order_movement"`
+	Actor         string         `gorm:"type:varchar(64);not null;comment:操作人"`
+	CreatedAt     time.Time
+}
+
+// StockDiscrepancy 描述产品的库存与流水汇总不一致
+type StockDiscrepancy struct {
+	ProductID      int64
+	Quantity       int64
+	LedgerQuantity int64
+	Reserved       int64
+	LedgerReserved int64
+}
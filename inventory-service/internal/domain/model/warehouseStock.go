@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// WarehouseStock 某个产品在某个仓库的库存。Product 上的 Quantity、Reserved 为各仓库之和，
+// 二者在同一事务中更新
+type WarehouseStock struct {
+	ID          uint   `gorm:"primaryKey"`
+	ProductID   int64  `gorm:"type:bigint;not null;comment:产品ID;uniqueIndex:idx_product_warehouse"`
+	WarehouseID string `gorm:"type:varchar(32);not null;comment:仓库ID;uniqueIndex:idx_product_warehouse"`
+	Quantity    int64  `gorm:"type:bigint;not null;default:0;comment:在库数量"`
+	Reserved    int64  `gorm:"type:bigint;not null;default:0;comment:已预留数量"`
+	UpdatedAt   time.Time
+}
+
+// Available 返回该仓库可供新订单预留的库存数量
+func (s *WarehouseStock) Available() int64 {
+	return s.Quantity - s.Reserved
+}
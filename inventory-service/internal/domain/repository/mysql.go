@@ -1,11 +1,16 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log"
 	"order-microsystem/inventory-service/internal/domain/model"
+	"order-microsystem/outbox"
+	"strings"
+	"time"
 )
 
 type MySQLRepository struct {
@@ -16,57 +21,995 @@ func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
 	return &MySQLRepository{db: db}
 }
 
-func (m *MySQLRepository) AutoMigrations() error {
-	err := m.db.AutoMigrate(&model.Product{})
+// AutoMigrations 迁移表结构并写入初始数据，引入多仓库前的库存与预留归入 defaultWarehouse
+func (m *MySQLRepository) AutoMigrations(defaultWarehouse string) error {
+	err := m.db.AutoMigrate(&model.Product{}, &model.WarehouseStock{}, &model.Reservation{},
+		&model.OutboxEvent{}, &model.StockMovement{}, &model.FlashSale{},
+		&model.Category{}, &model.ParentProduct{}, &model.ProductAttribute{}, &model.CancelledOrder{})
 	if err != nil {
 		return fmt.Errorf("failed to autoMigrate Product model: %v", err)
 	}
 	var total int64
 	m.db.Model(&model.Product{}).Count(&total)
-	if total > 0 {
-		return nil
+	if total == 0 {
+		preparedData := []model.Product{
+			{ProductID: 1001, ProductName: "IPhone11", Price: 799999, Quantity: 9999},
+			{ProductID: 1002, ProductName: "IPhone12", Price: 899999, Quantity: 9999},
+			{ProductID: 1003, ProductName: "IPhone13", Price: 999999, Quantity: 9999},
+		}
+		if err := m.db.Create(&preparedData).Error; err != nil {
+			log.Fatalf("failed to insert prepared data: %v", err)
+		}
+	}
+	if err := m.backfillWarehouseStocks(defaultWarehouse); err != nil {
+		return err
+	}
+	if err := m.backfillSKUs(); err != nil {
+		return err
+	}
+	return m.backfillOpeningMovements(defaultWarehouse)
+}
+
+// backfillSKUs 为引入 SKU 前创建的产品以产品ID补全 SKU
+func (m *MySQLRepository) backfillSKUs() error {
+	err := m.db.Unscoped().Model(&model.Product{}).
+		Where("sku IS NULL").
+		Update("sku", gorm.Expr("CAST(product_id AS CHAR)")).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill product skus: %v", err)
 	}
-	preparedData := []model.Product{
-		{ProductID: 1001, ProductName: "IPhone11", Price: 799999, Quantity: 9999},
-		{ProductID: 1002, ProductName: "IPhone12", Price: 899999, Quantity: 9999},
-		{ProductID: 1003, ProductName: "IPhone13", Price: 999999, Quantity: 9999},
+	return nil
+}
+
+// backfillWarehouseStocks 将尚无仓库库存的产品的库存整体归入默认仓库，并为未记录仓库的预留补上默认仓库
+func (m *MySQLRepository) backfillWarehouseStocks(defaultWarehouse string) error {
+	var products []model.Product
+	if err := m.db.Unscoped().
+		Where("NOT EXISTS (SELECT 1 FROM warehouse_stocks WHERE warehouse_stocks.product_id = products.product_id)").
+		Find(&products).Error; err != nil {
+		return fmt.Errorf("failed to find products without warehouse stocks: %v", err)
 	}
-	if err := m.db.Create(&preparedData).Error; err != nil {
-		log.Fatalf("failed to insert prepared data: %v", err)
+	for _, product := range products {
+		err := m.db.Create(&model.WarehouseStock{
+			ProductID:   product.ProductID,
+			WarehouseID: defaultWarehouse,
+			Quantity:    product.Quantity,
+			Reserved:    product.Reserved,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill warehouse stock of product %d: %v", product.ProductID, err)
+		}
+	}
+
+	err := m.db.Model(&model.Reservation{}).
+		Where("warehouse_id = '' AND status <> ?", model.ReservationStatusRejected).
+		Update("warehouse_id", defaultWarehouse).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill reservation warehouses: %v", err)
 	}
 	return nil
 }
 
+// backfillOpeningMovements 为尚无库存流水的产品补记期初流水，使流水汇总与引入流水前的库存一致
+func (m *MySQLRepository) backfillOpeningMovements(defaultWarehouse string) error {
+	var products []model.Product
+	if err := m.db.Unscoped().
+		Where("NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.product_id = products.product_id)").
+		Find(&products).Error; err != nil {
+		return fmt.Errorf("failed to find products without movements: %v", err)
+	}
+	for _, product := range products {
+		err := m.db.Create(&model.StockMovement{
+			ProductID:     product.ProductID,
+			WarehouseID:   defaultWarehouse,
+			Delta:         product.Quantity,
+			ReservedDelta: product.Reserved,
+			Quantity:      product.Quantity,
+			Reserved:      product.Reserved,
+			Reason:        model.MovementReasonInitial,
+			Actor:         model.MovementActorSystem,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill movement of product %d: %v", product.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// GetInventory 查询单个产品及其各仓库的库存明细
 func (m *MySQLRepository) GetInventory(productID int64) (*model.Product, error) {
 	var product model.Product
 	if err := m.db.Where("product_id = ?", productID).First(&product).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, err
-		}
+		return nil, err
+	}
+	if err := m.db.Where("product_id = ?", productID).
+		Order("warehouse_id ASC").
+		Find(&product.Stocks).Error; err != nil {
+		return nil, err
+	}
+	if err := m.loadAttributes([]*model.Product{&product}); err != nil {
+		return nil, err
 	}
 	return &product, nil
 }
 
-func (m *MySQLRepository) GetAllInventory(offset int32, limit int32) ([]*model.Product, error) {
-	var products []*model.Product
+// loadAttributes 批量加载产品的规格属性
+func (m *MySQLRepository) loadAttributes(products []*model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	productIDs := make([]int64, 0, len(products))
+	byID := make(map[int64]*model.Product, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ProductID)
+		byID[product.ProductID] = product
+	}
+	var attributes []*model.ProductAttribute
+	if err := m.db.Where("product_id IN ?", productIDs).
+		Order("`key` ASC").
+		Find(&attributes).Error; err != nil {
+		return err
+	}
+	for _, attribute := range attributes {
+		product := byID[attribute.ProductID]
+		product.Attributes = append(product.Attributes, attribute)
+	}
+	return nil
+}
 
-	result := m.db.Find(&products).Offset(int(offset)).Limit(int(limit))
+// SKUExists 返回 SKU 是否已被使用，已软删除的产品同样占用其 SKU
+func (m *MySQLRepository) SKUExists(sku string) (bool, error) {
+	var total int64
+	if err := m.db.Unscoped().Model(&model.Product{}).
+		Where("sku = ?", sku).
+		Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// ProductExists 返回产品ID是否已被使用，已软删除的产品同样占用其产品ID
+func (m *MySQLRepository) ProductExists(productID int64) (bool, error) {
+	var total int64
+	if err := m.db.Unscoped().Model(&model.Product{}).
+		Where("product_id = ?", productID).
+		Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// CreateProduct 新建产品及其规格属性，期初库存存放在 warehouseID 仓库并记录期初库存流水
+func (m *MySQLRepository) CreateProduct(product *model.Product, warehouseID string, actor string) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		for _, attribute := range product.Attributes {
+			attribute.ProductID = product.ProductID
+		}
+		if len(product.Attributes) > 0 {
+			if err := tx.Create(product.Attributes).Error; err != nil {
+				return err
+			}
+		}
+		stock := &model.WarehouseStock{
+			ProductID:   product.ProductID,
+			WarehouseID: warehouseID,
+			Quantity:    product.Quantity,
+		}
+		if err := tx.Create(stock).Error; err != nil {
+			return err
+		}
+		product.Stocks = []*model.WarehouseStock{stock}
+		return recordMovement(tx, &model.StockMovement{
+			ProductID:   product.ProductID,
+			WarehouseID: warehouseID,
+			Delta:       product.Quantity,
+			Reason:      model.MovementReasonInitial,
+			Actor:       actor,
+		})
+	})
+}
+
+// UpdateProduct 更新产品的基本信息，updates 的键为列名；attributes 不为 nil 时替换产品的全部规格属性
+func (m *MySQLRepository) UpdateProduct(productID int64, updates map[string]interface{}, attributes []*model.ProductAttribute) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		var product model.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", productID).
+			First(&product).Error; err != nil {
+			return err
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&product).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if attributes == nil {
+			return nil
+		}
+		if err := tx.Where("product_id = ?", productID).Delete(&model.ProductAttribute{}).Error; err != nil {
+			return err
+		}
+		for _, attribute := range attributes {
+			attribute.ProductID = productID
+		}
+		if len(attributes) == 0 {
+			return nil
+		}
+		return tx.Create(attributes).Error
+	})
+}
+
+// AdjustStock 仅当仓库调整后的在库数量不少于其已预留数量时原子地调整在库数量并记录流水，返回是否调整成功。
+// 产品不存在或已删除时返回 gorm.ErrRecordNotFound
+func (m *MySQLRepository) AdjustStock(productID int64, warehouseID string, delta int64, note string, actor string) (bool, error) {
+	adjusted := false
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var product model.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", productID).
+			First(&product).Error; err != nil {
+			return err
+		}
+
+		// 首次向仓库入库时创建该仓库的库存记录
+		if delta > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&model.WarehouseStock{ProductID: productID, WarehouseID: warehouseID}).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&model.WarehouseStock{}).
+			Where("product_id = ? AND warehouse_id = ? AND quantity + ? >= reserved", productID, warehouseID, delta).
+			Update("quantity", gorm.Expr("quantity + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&product).
+			Update("quantity", gorm.Expr("quantity + ?", delta)).Error; err != nil {
+			return err
+		}
+		adjusted = true
+
+		reason := model.MovementReasonAdjust
+		if delta > 0 {
+			reason = model.MovementReasonRestock
+		}
+		return recordMovement(tx, &model.StockMovement{
+			ProductID:   productID,
+			WarehouseID: warehouseID,
+			Delta:       delta,
+			Reason:      reason,
+			Note:        note,
+			Actor:       actor,
+		})
+	})
+	return adjusted, err
+}
+
+// DeleteProduct 仅当产品没有被预留的库存时软删除产品，返回是否删除成功
+func (m *MySQLRepository) DeleteProduct(productID int64) (bool, error) {
+	result := m.db.Where("product_id = ? AND reserved = 0", productID).
+		Delete(&model.Product{})
 	if result.Error != nil {
-		return nil, result.Error
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// GetProducts 按产品ID批量查询产品，不存在的产品会被忽略
+func (m *MySQLRepository) GetProducts(productIDs []int64) ([]*model.Product, error) {
+	var products []*model.Product
+	if err := m.db.Where("product_id IN ?", productIDs).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetCatalogProducts 按产品ID或 SKU 批量查询产品及其规格属性，不存在的产品会被忽略
+func (m *MySQLRepository) GetCatalogProducts(productIDs []int64, skus []string) ([]*model.Product, error) {
+	var products []*model.Product
+	if err := m.db.Where("product_id IN ? OR sku IN ?", productIDs, skus).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if err := m.loadAttributes(products); err != nil {
+		return nil, err
 	}
+	return products, nil
+}
 
+// ListAllProducts 返回全部未删除的产品
+func (m *MySQLRepository) ListAllProducts() ([]*model.Product, error) {
+	var products []*model.Product
+	if err := m.db.Order("product_id ASC").Find(&products).Error; err != nil {
+		return nil, err
+	}
 	return products, nil
 }
 
-func (m *MySQLRepository) UpdateInventory(productID int64, quantity int64) error {
+// ListProductsAfter 按产品ID顺序返回ID大于 afterID 的至多 limit 个产品及其各仓库的库存明细，用于导出
+func (m *MySQLRepository) ListProductsAfter(afterID int64, limit int) ([]*model.Product, error) {
+	var products []*model.Product
+	if err := m.db.Where("product_id > ?", afterID).
+		Order("product_id ASC").
+		Limit(limit).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return products, nil
+	}
+
+	productIDs := make([]int64, 0, len(products))
+	byID := make(map[int64]*model.Product, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ProductID)
+		byID[product.ProductID] = product
+	}
+	var stocks []*model.WarehouseStock
+	if err := m.db.Where("product_id IN ?", productIDs).
+		Order("warehouse_id ASC").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	for _, stock := range stocks {
+		product := byID[stock.ProductID]
+		product.Stocks = append(product.Stocks, stock)
+	}
+	return products, nil
+}
+
+// errImportDryRun 用于在试运行结束时回滚导入事务
+var errImportDryRun = errors.New("import dry run")
+
+// ImportProducts 按 batchSize 行一个事务依次导入产品，返回每行的处理结果。未通过校验的行不做修改，不影响同批的其他行。
+// dryRun 为 true 时全部行在同一事务中执行后回滚，结果与实际导入一致但不写入任何数据
+func (m *MySQLRepository) ImportProducts(rows []*model.ProductImportRow, batchSize int, dryRun bool, actor string) ([]*model.ProductImportResult, error) {
+	results := make([]*model.ProductImportResult, 0, len(rows))
+	importBatch := func(tx *gorm.DB, batch []*model.ProductImportRow) error {
+		for _, row := range batch {
+			result, err := importProduct(tx, row, actor)
+			if err != nil {
+				return fmt.Errorf("failed to import line %d: %v", row.Line, err)
+			}
+			results = append(results, result)
+		}
+		return nil
+	}
+
+	if dryRun {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := importBatch(tx, rows); err != nil {
+				return err
+			}
+			return errImportDryRun
+		})
+		if !errors.Is(err, errImportDryRun) {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+		if err := m.db.Transaction(func(tx *gorm.DB) error {
+			return importBatch(tx, batch)
+		}); err != nil {
+			// 之前的批次已提交，一并返回其结果
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// importProduct 新建或更新一行对应的产品，行与现有数据冲突时返回 failed 结果而不做修改
+func importProduct(tx *gorm.DB, row *model.ProductImportRow, actor string) (*model.ProductImportResult, error) {
+	result := &model.ProductImportResult{Line: row.Line, ProductID: row.ProductID}
+	fail := func(format string, args ...interface{}) (*model.ProductImportResult, error) {
+		result.Action = model.ProductImportFailed
+		result.Changes = nil
+		result.Error = fmt.Sprintf(format, args...)
+		return result, nil
+	}
+
+	var product model.Product
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ?", row.ProductID).
+		First(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if row.ProductName == nil || row.Price == nil {
+			return fail("product_name and price are required for new products")
+		}
+		// 导入的新产品以产品ID作为 SKU
+		if exists, err := (&MySQLRepository{db: tx}).SKUExists(model.DefaultSKU(row.ProductID)); err != nil {
+			return nil, err
+		} else if exists {
+			return fail("sku %s is already used by another product", model.DefaultSKU(row.ProductID))
+		}
+		product = model.Product{
+			ProductID:   row.ProductID,
+			SKU:         model.DefaultSKU(row.ProductID),
+			ProductName: *row.ProductName,
+			Price:       *row.Price,
+		}
+		if row.Quantity != nil {
+			product.Quantity = *row.Quantity
+		}
+		if row.ReorderThreshold != nil {
+			product.ReorderThreshold = *row.ReorderThreshold
+		}
+		if err := (&MySQLRepository{db: tx}).CreateProduct(&product, row.WarehouseID, actor); err != nil {
+			return nil, err
+		}
+		result.Action = model.ProductImportCreated
+		result.Changes = []string{
+			fmt.Sprintf("product_name: %q", product.ProductName),
+			fmt.Sprintf("price: %d", product.Price),
+			fmt.Sprintf("quantity[%s]: %d", row.WarehouseID, product.Quantity),
+			fmt.Sprintf("reorder_threshold: %d", product.ReorderThreshold),
+		}
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if product.DeletedAt.Valid {
+		return fail("product %d has been deleted", row.ProductID)
+	}
+
+	updates := make(map[string]interface{})
+	if row.ProductName != nil && *row.ProductName != product.ProductName {
+		updates["product_name"] = *row.ProductName
+		result.Changes = append(result.Changes, fmt.Sprintf("product_name: %q -> %q", product.ProductName, *row.ProductName))
+	}
+	if row.Price != nil && *row.Price != product.Price {
+		updates["price"] = *row.Price
+		result.Changes = append(result.Changes, fmt.Sprintf("price: %d -> %d", product.Price, *row.Price))
+	}
+	if row.ReorderThreshold != nil && *row.ReorderThreshold != product.ReorderThreshold {
+		updates["reorder_threshold"] = *row.ReorderThreshold
+		result.Changes = append(result.Changes, fmt.Sprintf("reorder_threshold: %d -> %d", product.ReorderThreshold, *row.ReorderThreshold))
+	}
+
+	var delta int64
+	if row.Quantity != nil {
+		var stock model.WarehouseStock
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND warehouse_id = ?", row.ProductID, row.WarehouseID).
+			First(&stock).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if *row.Quantity < stock.Reserved {
+			return fail("quantity %d is less than the %d reserved in warehouse %s", *row.Quantity, stock.Reserved, row.WarehouseID)
+		}
+		delta = *row.Quantity - stock.Quantity
+		if delta != 0 {
+			result.Changes = append(result.Changes, fmt.Sprintf("quantity[%s]: %d -> %d", row.WarehouseID, stock.Quantity, *row.Quantity))
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&product).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	if delta != 0 {
+		adjusted, err := (&MySQLRepository{db: tx}).AdjustStock(row.ProductID, row.WarehouseID, delta, "import", actor)
+		if err != nil {
+			return nil, err
+		}
+		if !adjusted {
+			return nil, fmt.Errorf("stock of product %d in warehouse %s changed during import", row.ProductID, row.WarehouseID)
+		}
+	}
+
+	result.Action = model.ProductImportUnchanged
+	if len(result.Changes) > 0 {
+		result.Action = model.ProductImportUpdated
+	}
+	return result, nil
+}
+
+// GetAllInventory 按过滤条件分页查询产品，返回当前页产品和满足条件的产品总数
+func (m *MySQLRepository) GetAllInventory(filter model.ProductFilter, sort model.ProductSort, offset int32, limit int32) ([]*model.Product, int64, error) {
+	query := m.db.Model(&model.Product{})
+	if filter.NameContains != "" {
+		query = query.Where("product_name LIKE ?", "%"+escapeLike(filter.NameContains)+"%")
+	}
+	if filter.MinPrice != nil {
+		query = query.Where("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where("price <= ?", *filter.MaxPrice)
+	}
+	if filter.MinQuantity != nil {
+		query = query.Where("quantity >= ?", *filter.MinQuantity)
+	}
+	if filter.MaxQuantity != nil {
+		query = query.Where("quantity <= ?", *filter.MaxQuantity)
+	}
+	if filter.CategoryIDs != nil {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if filter.ParentProductID != nil {
+		query = query.Where("parent_product_id = ?", *filter.ParentProductID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 以产品ID作为次要排序，使排序字段相同的产品在翻页时顺序稳定
+	var products []*model.Product
+	err := query.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Column, Raw: true}, Desc: sort.Descending}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "product_id"}, Desc: sort.Descending}).
+		Offset(int(offset)).
+		Limit(int(limit)).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := m.loadAttributes(products); err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符，使其按字面匹配
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// Transaction 在一个数据库事务中执行 fn，fn 中通过 txRepo 进行的读写要么全部提交，要么全部回滚
+func (m *MySQLRepository) Transaction(fn func(txRepo *MySQLRepository) error) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&MySQLRepository{db: tx})
+	})
+}
+
+// AddOutboxEvent 将领域事件写入发件箱，应与对应的库存变更处于同一事务中
+func (m *MySQLRepository) AddOutboxEvent(event *model.OutboxEvent) error {
+	return m.db.Create(event).Error
+}
+
+// ClaimPending 按写入顺序领取尚未发送的发件箱事件。以 SKIP LOCKED 跳过其他副本正在领取的行，
+// 领取后的 lease 内其他副本不会再领取这些事件
+func (m *MySQLRepository) ClaimPending(ctx context.Context, owner string, lease time.Duration, limit int) ([]*outbox.Event, error) {
+	var events []*model.OutboxEvent
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND (claimed_by = ? OR claimed_until IS NULL OR claimed_until < ?)", owner, now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		return tx.Model(&model.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{"claimed_by": owner, "claimed_until": now.Add(lease)}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*outbox.Event, 0, len(events))
+	for _, event := range events {
+		claimed = append(claimed, &outbox.Event{
+			ID:         event.ID,
+			RoutingKey: event.RoutingKey,
+			Payload:    event.Payload,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return claimed, nil
+}
+
+// MarkSent 将发件箱事件标记为已发送
+func (m *MySQLRepository) MarkSent(ctx context.Context, id string) error {
+	return m.db.WithContext(ctx).Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Update("sent_at", time.Now()).Error
+}
+
+// LockWarehouseStocks 加锁读取产品在各仓库的库存，应在事务中调用，
+// 使分配仓库到预留完成期间库存不被并发修改。按固定顺序加锁以避免死锁
+func (m *MySQLRepository) LockWarehouseStocks(productIDs []int64) ([]*model.WarehouseStock, error) {
+	var stocks []*model.WarehouseStock
+	err := m.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id IN ?", productIDs).
+		Order("product_id ASC, warehouse_id ASC").
+		Find(&stocks).Error
+	return stocks, err
+}
+
+// ReserveStock 仅当仓库可用库存不少于 quantity 时原子地为订单增加预留数量并记录流水，返回是否预留成功，
+// 应在事务中调用使流水与预留数量一同提交或回滚。已删除的产品不能被预留
+func (m *MySQLRepository) ReserveStock(productID int64, warehouseID string, quantity int64, orderID string) (bool, error) {
 	result := m.db.Model(&model.Product{}).
 		Where("product_id = ?", productID).
-		Update("quantity", quantity)
+		Update("reserved", gorm.Expr("reserved + ?", quantity))
 	if result.Error != nil {
-		return result.Error
+		return false, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return false, nil
+	}
+	result = m.db.Model(&model.WarehouseStock{}).
+		Where("product_id = ? AND warehouse_id = ? AND quantity - reserved >= ?", productID, warehouseID, quantity).
+		Update("reserved", gorm.Expr("reserved + ?", quantity))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// 产品上的预留随调用方的事务一同回滚
+		return false, nil
+	}
+	err := recordMovement(m.db, &model.StockMovement{
+		ProductID:     productID,
+		WarehouseID:   warehouseID,
+		ReservedDelta: quantity,
+		Reason:        model.MovementReasonReserve,
+		OrderID:       orderID,
+		Actor:         model.MovementActorSystem,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *MySQLRepository) CreateReservation(reservation *model.Reservation) error {
+	return m.db.Create(reservation).Error
+}
+
+// GetReservations 返回订单的全部库存预留记录
+func (m *MySQLRepository) GetReservations(orderID string) ([]*model.Reservation, error) {
+	var reservations []*model.Reservation
+	if err := m.db.Where("order_id = ?", orderID).Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// MarkOrderCancelled 记录订单已取消，应与归还订单库存处于同一事务中；重复记录不会报错
+func (m *MySQLRepository) MarkOrderCancelled(orderID string) error {
+	return m.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.CancelledOrder{OrderID: orderID, CancelledAt: time.Now()}).Error
+}
+
+// IsOrderCancelled 判断订单是否已取消，只能通过 Transaction 中的仓储调用：以加锁读查询，记录不存在时同样会锁住该订单ID，
+// 并发的取消需等待本事务提交后才能写入，从而能看到本事务写入的预留并将其归还。事务外调用时锁会立即释放，没有意义
+func (m *MySQLRepository) IsOrderCancelled(orderID string) (bool, error) {
+	var cancelled []model.CancelledOrder
+	if err := m.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		Limit(1).
+		Find(&cancelled).Error; err != nil {
+		return false, err
+	}
+	return len(cancelled) > 0, nil
+}
+
+// ReleaseReservations 在一个事务中归还订单的库存：仍在预留中的释放预留数量，已提交的加回在库总数，
+// 返回是否有库存被归还；重复调用不会重复归还
+func (m *MySQLRepository) ReleaseReservations(orderID string) (bool, error) {
+	released := false
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var reservations []model.Reservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND status IN ?", orderID,
+				[]model.ReservationStatus{model.ReservationStatusLocked, model.ReservationStatusCommitted}).
+			Find(&reservations).Error; err != nil {
+			return err
+		}
+
+		for _, reservation := range reservations {
+			if err := releaseReservation(tx, &reservation, model.MovementActorSystem); err != nil {
+				return err
+			}
+		}
+		released = len(reservations) > 0
+		return nil
+	})
+	return released, err
+}
+
+// CommitReservations 在一个事务中提交订单仍在预留中的库存：从在库总数和预留数量中同时扣除，
+// 返回是否有预留被提交；重复调用不会重复扣除
+func (m *MySQLRepository) CommitReservations(orderID string) (bool, error) {
+	committed := false
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var reservations []model.Reservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND status = ?", orderID, model.ReservationStatusLocked).
+			Find(&reservations).Error; err != nil {
+			return err
+		}
+
+		for _, reservation := range reservations {
+			updates := map[string]interface{}{
+				"quantity": gorm.Expr("quantity - ?", reservation.Quantity),
+				"reserved": gorm.Expr("reserved - ?", reservation.Quantity),
+			}
+			if err := updateStock(tx, &reservation, updates); err != nil {
+				return err
+			}
+			if err := recordMovement(tx, &model.StockMovement{
+				ProductID:     reservation.ProductID,
+				WarehouseID:   reservation.WarehouseID,
+				Delta:         -reservation.Quantity,
+				ReservedDelta: -reservation.Quantity,
+				Reason:        model.MovementReasonCommit,
+				OrderID:       reservation.OrderID,
+				Actor:         model.MovementActorSystem,
+			}); err != nil {
+				return err
+			}
+			if err := tx.Model(&reservation).
+				Update("status", model.ReservationStatusCommitted).Error; err != nil {
+				return err
+			}
+		}
+		committed = len(reservations) > 0
+		return nil
+	})
+	return committed, err
+}
+
+// ReleaseExpiredReservations 归还最多 limit 条已过期仍未支付的预留，返回涉及的订单ID。
+// 使用 SKIP LOCKED 使多个副本可以同时清理而不会重复归还
+func (m *MySQLRepository) ReleaseExpiredReservations(now time.Time, limit int) ([]string, error) {
+	var orderIDs []string
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var reservations []model.Reservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at < ?", model.ReservationStatusLocked, now).
+			Order("expires_at ASC").
+			Limit(limit).
+			Find(&reservations).Error; err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool)
+		for _, reservation := range reservations {
+			if err := releaseReservation(tx, &reservation, model.MovementActorHoldSweeper); err != nil {
+				return err
+			}
+			if !seen[reservation.OrderID] {
+				seen[reservation.OrderID] = true
+				orderIDs = append(orderIDs, reservation.OrderID)
+			}
+		}
+		return nil
+	})
+	return orderIDs, err
+}
+
+// releaseReservation 归还单条预留占用的库存、记录流水并将其标记为已归还。
+// 产品已被删除时仍归还库存，使流水汇总与库存保持一致
+func releaseReservation(tx *gorm.DB, reservation *model.Reservation, actor string) error {
+	movement := &model.StockMovement{
+		ProductID:   reservation.ProductID,
+		WarehouseID: reservation.WarehouseID,
+		Reason:      model.MovementReasonRelease,
+		OrderID:     reservation.OrderID,
+		Actor:       actor,
+	}
+	column := "reserved"
+	expr := gorm.Expr("reserved - ?", reservation.Quantity)
+	movement.ReservedDelta = -reservation.Quantity
+	if reservation.Status == model.ReservationStatusCommitted {
+		column = "quantity"
+		expr = gorm.Expr("quantity + ?", reservation.Quantity)
+		movement.ReservedDelta = 0
+		movement.Delta = reservation.Quantity
+	}
+	if err := updateStock(tx, reservation, map[string]interface{}{column: expr}); err != nil {
+		return err
+	}
+	if err := recordMovement(tx, movement); err != nil {
+		return err
+	}
+	return tx.Model(reservation).
+		Update("status", model.ReservationStatusReleased).Error
+}
+
+// updateStock 同时更新预留所在仓库的库存与产品的库存合计
+func updateStock(tx *gorm.DB, reservation *model.Reservation, updates map[string]interface{}) error {
+	if err := tx.Model(&model.WarehouseStock{}).
+		Where("product_id = ? AND warehouse_id = ?", reservation.ProductID, reservation.WarehouseID).
+		Updates(updates).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&model.Product{}).
+		Where("product_id = ?", reservation.ProductID).
+		Updates(updates).Error
+}
+
+// recordMovement 以仓库变动后的库存补全流水并写入，须在库存变更之后、同一事务中调用
+func recordMovement(tx *gorm.DB, movement *model.StockMovement) error {
+	var stock model.WarehouseStock
+	if err := tx.Where("product_id = ? AND warehouse_id = ?", movement.ProductID, movement.WarehouseID).
+		First(&stock).Error; err != nil {
+		return fmt.Errorf("failed to read stock of product %d in warehouse %s: %v",
+			movement.ProductID, movement.WarehouseID, err)
+	}
+	movement.Quantity = stock.Quantity
+	movement.Reserved = stock.Reserved
+	if err := tx.Create(movement).Error; err != nil {
+		return fmt.Errorf("failed to record stock movement: %v", err)
 	}
 	return nil
 }
+
+// ListStockMovements 按时间倒序返回库存流水，productID 为 0、orderID 为空时不按其过滤，
+// beforeID 大于 0 时只返回ID小于 beforeID 的流水，用于翻页
+func (m *MySQLRepository) ListStockMovements(productID int64, orderID string, beforeID uint, limit int) ([]*model.StockMovement, error) {
+	query := m.db.Model(&model.StockMovement{})
+	if productID > 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+	if orderID != "" {
+		query = query.Where("order_id = ?", orderID)
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var movements []*model.StockMovement
+	if err := query.Order("id DESC").Limit(limit).Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// FindStockDiscrepancies 返回在库总数或预留数量与库存流水汇总不一致的产品，包括已删除的产品
+func (m *MySQLRepository) FindStockDiscrepancies() ([]*model.StockDiscrepancy, error) {
+	var discrepancies []*model.StockDiscrepancy
+	err := m.db.Raw(`
+		SELECT p.product_id, p.quantity, p.reserved,
+			COALESCE(SUM(s.delta), 0) AS ledger_quantity,
+			COALESCE(SUM(s.reserved_delta), 0) AS ledger_reserved
+		FROM products p
+		LEFT JOIN stock_movements s ON s.product_id = p.product_id
+		GROUP BY p.product_id, p.quantity, p.reserved
+		HAVING ledger_quantity <> p.quantity OR ledger_reserved <> p.reserved
+		ORDER BY p.product_id`).
+		Scan(&discrepancies).Error
+	return discrepancies, err
+}
+
+// GetOpenFlashSale 返回产品尚未对账的秒杀，没有时返回 gorm.ErrRecordNotFound
+func (m *MySQLRepository) GetOpenFlashSale(productID int64) (*model.FlashSale, error) {
+	var sale model.FlashSale
+	err := m.db.Where("product_id = ? AND status <> ?", productID, model.FlashSaleStatusReconciled).
+		Order("id DESC").
+		First(&sale).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// HasOpenFlashSale 返回产品中是否有尚未对账的秒杀
+func (m *MySQLRepository) HasOpenFlashSale(productIDs []int64) (bool, error) {
+	var count int64
+	err := m.db.Model(&model.FlashSale{}).
+		Where("product_id IN ? AND status <> ?", productIDs, model.FlashSaleStatusReconciled).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (m *MySQLRepository) CreateFlashSale(sale *model.FlashSale) error {
+	return m.db.Create(sale).Error
+}
+
+// DeleteFlashSale 删除未能在 Redis 中预热的秒杀记录
+func (m *MySQLRepository) DeleteFlashSale(sale *model.FlashSale) error {
+	return m.db.Unscoped().Delete(sale).Error
+}
+
+func (m *MySQLRepository) SaveFlashSale(sale *model.FlashSale) error {
+	return m.db.Save(sale).Error
+}
+
+// AddFlashSalePersisted 累加产品当前秒杀的已落库数量，应与订单的库存预留处于同一事务中
+func (m *MySQLRepository) AddFlashSalePersisted(productID int64, quantity int64) error {
+	return m.db.Model(&model.FlashSale{}).
+		Where("product_id = ? AND status <> ?", productID, model.FlashSaleStatusReconciled).
+		Update("persisted", gorm.Expr("persisted + ?", quantity)).Error
+}
+
+// CreateCategory 新建分类
+func (m *MySQLRepository) CreateCategory(category *model.Category) error {
+	return m.db.Create(category).Error
+}
+
+// ListCategories 按ID顺序返回全部分类
+func (m *MySQLRepository) ListCategories() ([]*model.Category, error) {
+	var categories []*model.Category
+	if err := m.db.Order("id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// CategoryExists 返回分类是否存在
+func (m *MySQLRepository) CategoryExists(id uint) (bool, error) {
+	var total int64
+	if err := m.db.Model(&model.Category{}).Where("id = ?", id).Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// DeleteCategory 仅当分类没有子分类且没有产品时删除分类，返回是否删除成功。分类不存在时返回 gorm.ErrRecordNotFound
+func (m *MySQLRepository) DeleteCategory(id uint) (bool, error) {
+	deleted := false
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var category model.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+			return err
+		}
+		var children, products int64
+		if err := tx.Model(&model.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Product{}).Where("category_id = ?", id).Count(&products).Error; err != nil {
+			return err
+		}
+		if children > 0 || products > 0 {
+			return nil
+		}
+		deleted = true
+		return tx.Delete(&category).Error
+	})
+	return deleted, err
+}
+
+// CreateParentProduct 新建多规格商品的父产品
+func (m *MySQLRepository) CreateParentProduct(parent *model.ParentProduct) error {
+	return m.db.Create(parent).Error
+}
+
+// GetParentProduct 查询父产品及其各规格的产品，规格按产品ID排序
+func (m *MySQLRepository) GetParentProduct(id uint) (*model.ParentProduct, error) {
+	var parent model.ParentProduct
+	if err := m.db.First(&parent, id).Error; err != nil {
+		return nil, err
+	}
+	if err := m.db.Where("parent_product_id = ?", id).
+		Order("product_id ASC").
+		Find(&parent.Variants).Error; err != nil {
+		return nil, err
+	}
+	if err := m.loadAttributes(parent.Variants); err != nil {
+		return nil, err
+	}
+	return &parent, nil
+}
+
+// ParentProductExists 返回父产品是否存在
+func (m *MySQLRepository) ParentProductExists(id uint) (bool, error) {
+	var total int64
+	if err := m.db.Model(&model.ParentProduct{}).Where("id = ?", id).Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
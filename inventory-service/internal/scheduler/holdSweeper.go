@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// holdBatchSize 每轮最多归还的预留记录数量
+	holdBatchSize = 100
+	// defaultHoldSweepInterval 未配置扫描间隔时使用的默认值
+	defaultHoldSweepInterval = time.Minute
+)
+
+// ExpiredHoldReleaser 归还已过期的库存预留
+type ExpiredHoldReleaser interface {
+	ReleaseExpiredReservations(now time.Time, limit int) ([]string, error)
+}
+
+// HoldSweeper 周期性归还超时仍未支付的库存预留，
+// 预留记录以 SKIP LOCKED 方式加锁，多个副本同时运行时不会重复归还
+type HoldSweeper struct {
+	releaser ExpiredHoldReleaser
+	interval time.Duration
+}
+
+func NewHoldSweeper(releaser ExpiredHoldReleaser, interval time.Duration) *HoldSweeper {
+	if interval <= 0 {
+		interval = defaultHoldSweepInterval
+	}
+	return &HoldSweeper{
+		releaser: releaser,
+		interval: interval,
+	}
+}
+
+// Run 持续归还过期预留，直到 ctx 被取消
+func (s *HoldSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *HoldSweeper) sweep(ctx context.Context) {
+	// 满批次时继续处理，直到没有积压的过期预留
+	for ctx.Err() == nil {
+		orderIDs, err := s.releaser.ReleaseExpiredReservations(time.Now(), holdBatchSize)
+		if err != nil {
+			log.Printf("hold sweeper: %v", err)
+			return
+		}
+		if len(orderIDs) == 0 {
+			return
+		}
+		log.Printf("hold sweeper: released expired holds of orders %v", orderIDs)
+	}
+}
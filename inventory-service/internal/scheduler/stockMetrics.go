@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"order-microsystem/inventory-service/internal/domain/model"
+	"order-microsystem/inventory-service/pkg/monitoring"
+	"strconv"
+	"time"
+)
+
+// defaultStockMetricsInterval 未配置刷新间隔时使用的默认值
+const defaultStockMetricsInterval = 30 * time.Second
+
+// ProductLister 返回全部未删除的产品
+type ProductLister interface {
+	ListAllProducts() ([]*model.Product, error)
+}
+
+// StockMetrics 周期性地从数据库刷新各产品的库存指标，
+// 多个副本各自上报的是同一份数据库快照，告警规则无需按副本聚合
+type StockMetrics struct {
+	lister   ProductLister
+	interval time.Duration
+}
+
+func NewStockMetrics(lister ProductLister, interval time.Duration) *StockMetrics {
+	if interval <= 0 {
+		interval = defaultStockMetricsInterval
+	}
+	return &StockMetrics{
+		lister:   lister,
+		interval: interval,
+	}
+}
+
+// Run 持续刷新库存指标，直到 ctx 被取消
+func (m *StockMetrics) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	m.refresh()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refresh()
+		}
+	}
+}
+
+func (m *StockMetrics) refresh() {
+	products, err := m.lister.ListAllProducts()
+	if err != nil {
+		log.Printf("stock metrics: %v", err)
+		return
+	}
+
+	// 已删除的产品不再上报
+	monitoring.Stock.Reset()
+	monitoring.ReorderThreshold.Reset()
+	for _, product := range products {
+		productID := strconv.FormatInt(product.ProductID, 10)
+		monitoring.Stock.WithLabelValues(productID, "quantity").Set(float64(product.Quantity))
+		monitoring.Stock.WithLabelValues(productID, "reserved").Set(float64(product.Reserved))
+		monitoring.Stock.WithLabelValues(productID, "available").Set(float64(product.Available()))
+		monitoring.ReorderThreshold.WithLabelValues(productID).Set(float64(product.ReorderThreshold))
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"order-microsystem/inventory-service/internal/domain/model"
+	"unicode/utf8"
+)
+
+type CatalogRepository interface {
+	CreateCategory(category *model.Category) error
+	ListCategories() ([]*model.Category, error)
+	CategoryExists(id uint) (bool, error)
+	DeleteCategory(id uint) (bool, error)
+	CreateParentProduct(parent *model.ParentProduct) error
+	GetParentProduct(id uint) (*model.ParentProduct, error)
+}
+
+const (
+	// maxCategoryNameLength 分类名的最大长度，与 categories.name 列宽一致
+	maxCategoryNameLength = 60
+	// maxDescriptionLength 父产品描述的最大长度，与 parent_products.description 列宽一致
+	maxDescriptionLength = 255
+)
+
+// CatalogService 管理产品分类树和多规格商品的父产品，各规格作为产品由 InventoryService 管理
+type CatalogService struct {
+	repo CatalogRepository
+}
+
+func NewCatalogService(repo CatalogRepository) *CatalogService {
+	return &CatalogService{repo: repo}
+}
+
+// CreateCategory 新建分类，parentID 为 nil 时新建顶级分类
+func (s *CatalogService) CreateCategory(name string, parentID *uint) (*model.Category, error) {
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", maxCategoryNameLength)
+	}
+	if parentID != nil {
+		exists, err := s.repo.CategoryExists(*parentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check parent category: %v", err)
+		}
+		if !exists {
+			return nil, status.Errorf(codes.InvalidArgument, "parent category %d not found", *parentID)
+		}
+	}
+
+	category := &model.Category{Name: name, ParentID: parentID}
+	if err := s.repo.CreateCategory(category); err != nil {
+		return nil, fmt.Errorf("failed to create category: %v", err)
+	}
+	return category, nil
+}
+
+// ListCategories 返回全部分类，调用方可按 ParentID 组装分类树
+func (s *CatalogService) ListCategories() ([]*model.Category, error) {
+	categories, err := s.repo.ListCategories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %v", err)
+	}
+	return categories, nil
+}
+
+// DeleteCategory 删除分类，仍有子分类或产品的分类不能删除
+func (s *CatalogService) DeleteCategory(id uint) error {
+	if id == 0 {
+		return status.Error(codes.InvalidArgument, "category_id must be positive")
+	}
+	deleted, err := s.repo.DeleteCategory(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status.Errorf(codes.NotFound, "category %d not found", id)
+		}
+		return fmt.Errorf("failed to delete category: %v", err)
+	}
+	if !deleted {
+		return status.Errorf(codes.FailedPrecondition, "category %d still has subcategories or products", id)
+	}
+	return nil
+}
+
+// CreateParentProduct 新建多规格商品的父产品，之后以 parent_product_id 创建各规格的产品
+func (s *CatalogService) CreateParentProduct(name string, description string) (*model.ParentProduct, error) {
+	if err := validateProductName(name); err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description must be at most %d characters", maxDescriptionLength)
+	}
+
+	parent := &model.ParentProduct{Name: name, Description: description}
+	if err := s.repo.CreateParentProduct(parent); err != nil {
+		return nil, fmt.Errorf("failed to create parent product: %v", err)
+	}
+	return parent, nil
+}
+
+// GetParentProduct 查询父产品及其各规格的产品
+func (s *CatalogService) GetParentProduct(id uint) (*model.ParentProduct, error) {
+	if id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "parent_product_id must be positive")
+	}
+	parent, err := s.repo.GetParentProduct(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "parent product %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get parent product: %v", err)
+	}
+	return parent, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"order-microsystem/inventory-service/internal/domain/model"
+	"time"
+)
+
+type FlashSaleRepository interface {
+	GetInventory(productID int64) (*model.Product, error)
+	GetOpenFlashSale(productID int64) (*model.FlashSale, error)
+	CreateFlashSale(sale *model.FlashSale) error
+	DeleteFlashSale(sale *model.FlashSale) error
+	SaveFlashSale(sale *model.FlashSale) error
+}
+
+// FlashSaleCache 秒杀库存在 Redis 中的计数
+type FlashSaleCache interface {
+	StartFlashSale(productID int64, quantity int64) (bool, error)
+	StopFlashSale(productID int64) (remaining int64, sold int64, err error)
+	FlashSaleSold(productID int64) (int64, error)
+	FlashSaleBacklog() (int64, error)
+	ClearFlashSale(productID int64) error
+}
+
+// FlashSaleService 管理秒杀的开始、停止与对账。秒杀期间产品的订单在 Redis 中预扣库存，
+// 预扣成功的订单由落库任务异步写入 MySQL
+type FlashSaleService struct {
+	repo  FlashSaleRepository
+	cache FlashSaleCache
+}
+
+func NewFlashSaleService(repo FlashSaleRepository, cache FlashSaleCache) *FlashSaleService {
+	return &FlashSaleService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// StartFlashSale 将产品的 quantity 件库存预热到 Redis 开始秒杀，quantity 不能超过产品当前的可用库存。
+// 上一次秒杀对账完成前不能开始新的秒杀
+func (s *FlashSaleService) StartFlashSale(productID int64, quantity int64) (*model.FlashSale, error) {
+	if productID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	if quantity <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+
+	product, err := s.repo.GetInventory(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", productID)
+		}
+		return nil, fmt.Errorf("failed to get product: %v", err)
+	}
+	if quantity > product.Available() {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"product %d has only %d available", productID, product.Available())
+	}
+
+	if _, err := s.repo.GetOpenFlashSale(productID); err == nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"product %d has a flash sale that is not reconciled yet", productID)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get flash sale: %v", err)
+	}
+
+	sale := &model.FlashSale{
+		ProductID: productID,
+		Quantity:  quantity,
+		Status:    model.FlashSaleStatusActive,
+	}
+	if err := s.repo.CreateFlashSale(sale); err != nil {
+		return nil, fmt.Errorf("failed to create flash sale: %v", err)
+	}
+	started, err := s.cache.StartFlashSale(productID, quantity)
+	if err != nil || !started {
+		if deleteErr := s.repo.DeleteFlashSale(sale); deleteErr != nil {
+			return nil, fmt.Errorf("failed to delete flash sale: %v", deleteErr)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "failed to preload flash sale stock: %v", err)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "product %d is already on flash sale", productID)
+	}
+	return sale, nil
+}
+
+// StopFlashSale 停止产品的秒杀，之后的订单按普通订单处理，记录停止时 Redis 中的剩余与已售数量
+func (s *FlashSaleService) StopFlashSale(productID int64) (*model.FlashSale, error) {
+	sale, err := s.getOpenFlashSale(productID)
+	if err != nil {
+		return nil, err
+	}
+	if sale.Status != model.FlashSaleStatusActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "flash sale of product %d is already stopped", productID)
+	}
+
+	remaining, sold, err := s.cache.StopFlashSale(productID)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to stop flash sale: %v", err)
+	}
+	now := time.Now()
+	sale.Remaining = max(remaining, 0)
+	sale.Sold = sold
+	sale.Status = model.FlashSaleStatusStopped
+	sale.StoppedAt = &now
+	if err := s.repo.SaveFlashSale(sale); err != nil {
+		return nil, fmt.Errorf("failed to save flash sale: %v", err)
+	}
+	return sale, nil
+}
+
+// ReconcileFlashSale 核对已停止秒杀在 Redis 中的已售数量与 MySQL 中的已落库数量，返回待落库的订单数量和是否一致。
+// 待落库订单全部处理完且数量一致时将秒杀标记为已对账并清理 Redis 计数
+func (s *FlashSaleService) ReconcileFlashSale(productID int64) (*model.FlashSale, int64, bool, error) {
+	sale, err := s.getOpenFlashSale(productID)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if sale.Status == model.FlashSaleStatusActive {
+		return nil, 0, false, status.Errorf(codes.FailedPrecondition,
+			"flash sale of product %d must be stopped before reconciling", productID)
+	}
+
+	backlog, err := s.cache.FlashSaleBacklog()
+	if err != nil {
+		return nil, 0, false, status.Errorf(codes.Unavailable, "failed to read flash sale backlog: %v", err)
+	}
+	sold, err := s.cache.FlashSaleSold(productID)
+	if err != nil {
+		return nil, 0, false, status.Errorf(codes.Unavailable, "failed to read flash sale sold count: %v", err)
+	}
+
+	// 落库失败归还的库存会从已售数量中扣除，因此一致时已售数量等于已落库数量
+	sale.Sold = sold
+	consistent := backlog == 0 && sale.Sold == sale.Persisted
+	if consistent {
+		now := time.Now()
+		sale.Status = model.FlashSaleStatusReconciled
+		sale.ReconciledAt = &now
+	}
+	if err := s.repo.SaveFlashSale(sale); err != nil {
+		return nil, 0, false, fmt.Errorf("failed to save flash sale: %v", err)
+	}
+	if consistent {
+		if err := s.cache.ClearFlashSale(productID); err != nil {
+			return nil, 0, false, status.Errorf(codes.Unavailable, "failed to clear flash sale counters: %v", err)
+		}
+	}
+	return sale, backlog, consistent, nil
+}
+
+func (s *FlashSaleService) getOpenFlashSale(productID int64) (*model.FlashSale, error) {
+	if productID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	sale, err := s.repo.GetOpenFlashSale(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %d has no open flash sale", productID)
+		}
+		return nil, fmt.Errorf("failed to get flash sale: %v", err)
+	}
+	return sale, nil
+}
@@ -1,20 +1,83 @@
 package service
 
-import "order-microsystem/inventory-service/internal/domain/model"
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"order-microsystem/inventory-service/internal/domain/model"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
 
 type InventoryRepository interface {
-	UpdateInventory(product_id int64, quantity int64) error
 	GetInventory(product_id int64) (*model.Product, error)
-	GetAllInventory(offset int32, limit int32) ([]*model.Product, error)
+	GetAllInventory(filter model.ProductFilter, sort model.ProductSort, offset int32, limit int32) ([]*model.Product, int64, error)
+	GetReservations(orderID string) ([]*model.Reservation, error)
+	GetProducts(productIDs []int64) ([]*model.Product, error)
+	ProductExists(productID int64) (bool, error)
+	CreateProduct(product *model.Product, warehouseID string, actor string) error
+	UpdateProduct(productID int64, updates map[string]interface{}, attributes []*model.ProductAttribute) error
+	AdjustStock(productID int64, warehouseID string, delta int64, note string, actor string) (bool, error)
+	DeleteProduct(productID int64) (bool, error)
+	ListStockMovements(productID int64, orderID string, beforeID uint, limit int) ([]*model.StockMovement, error)
+	FindStockDiscrepancies() ([]*model.StockDiscrepancy, error)
+	ListProductsAfter(afterID int64, limit int) ([]*model.Product, error)
+	ImportProducts(rows []*model.ProductImportRow, batchSize int, dryRun bool, actor string) ([]*model.ProductImportResult, error)
+	GetCatalogProducts(productIDs []int64, skus []string) ([]*model.Product, error)
+	SKUExists(sku string) (bool, error)
+	CategoryExists(id uint) (bool, error)
+	ListCategories() ([]*model.Category, error)
+	ParentProductExists(id uint) (bool, error)
 }
 
+const (
+	// maxBatchProducts 单次批量查询的最大产品数量
+	maxBatchProducts = 100
+	// maxProductNameLength 产品名的最大长度，与 products.product_name 列宽一致
+	maxProductNameLength = 60
+	// maxNoteLength 库存调整原因的最大长度，与 stock_movements.note 列宽一致
+	maxNoteLength = 255
+	// maxSKULength SKU 的最大长度，与 products.sku 列宽一致
+	maxSKULength = 64
+	// maxAttributes 单个产品的最大规格属性数量
+	maxAttributes = 20
+	// maxAttributeKeyLength、maxAttributeValueLength 与 product_attributes 的列宽一致
+	maxAttributeKeyLength   = 32
+	maxAttributeValueLength = 64
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// maxImportRows 单次导入的最大行数
+	maxImportRows = 10000
+	// importBatchSize 导入时每个事务写入的行数
+	importBatchSize = 100
+	// exportBatchSize 导出时每次从数据库读取的产品数量
+	exportBatchSize = 200
+)
+
 type InventoryService struct {
 	repo InventoryRepository
+	// warehouses 已配置的仓库ID
+	warehouses map[string]bool
+	// defaultWarehouse 未指定仓库的入库操作使用的仓库
+	defaultWarehouse string
 }
 
-func NewInventoryService(repo InventoryRepository) *InventoryService {
+func NewInventoryService(repo InventoryRepository, warehouseIDs []string, defaultWarehouse string) *InventoryService {
+	warehouses := make(map[string]bool, len(warehouseIDs))
+	for _, id := range warehouseIDs {
+		warehouses[id] = true
+	}
 	return &InventoryService{
-		repo: repo,
+		repo:             repo,
+		warehouses:       warehouses,
+		defaultWarehouse: defaultWarehouse,
 	}
 }
 
@@ -26,19 +89,515 @@ func (s *InventoryService) GetInventory(productID int64) (*model.Product, error)
 	return result, nil
 }
 
-func (s *InventoryService) GetAllInventory(offset int32, limit int32) ([]*model.Product, error) {
-	results, err := s.repo.GetAllInventory(offset, limit)
+// productSortColumns 允许排序的字段及其对应的列或表达式
+var productSortColumns = map[string]string{
+	"product_id":   "product_id",
+	"product_name": "product_name",
+	"price":        "price",
+	"quantity":     "quantity",
+	"available":    "quantity - reserved",
+}
+
+// GetAllInventory 按过滤条件和排序方式分页查询产品，返回当前页产品和满足条件的产品总数
+func (s *InventoryService) GetAllInventory(filter model.ProductFilter, sortBy string, descending bool, offset int32, limit int32) ([]*model.Product, int64, error) {
+	if offset < 0 {
+		return nil, 0, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, 0, status.Error(codes.InvalidArgument, "min_price must not be greater than max_price")
+	}
+	if filter.MinQuantity != nil && filter.MaxQuantity != nil && *filter.MinQuantity > *filter.MaxQuantity {
+		return nil, 0, status.Error(codes.InvalidArgument, "min_quantity must not be greater than max_quantity")
+	}
+
+	if filter.CategoryIDs != nil {
+		categoryIDs, err := s.expandCategories(filter.CategoryIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.CategoryIDs = categoryIDs
+	}
+
+	if sortBy == "" {
+		sortBy = "product_id"
+	}
+	column, ok := productSortColumns[sortBy]
+	if !ok {
+		return nil, 0, status.Errorf(codes.InvalidArgument, "invalid sort_by: %s", sortBy)
+	}
+
+	products, total, err := s.repo.GetAllInventory(filter, model.ProductSort{Column: column, Descending: descending}, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list inventory: %v", err)
+	}
+	return products, total, nil
+}
+
+// BatchGetProducts 按产品ID或 SKU 批量查询产品及其规格属性，不存在的产品不会出现在结果中
+func (s *InventoryService) BatchGetProducts(productIDs []int64, skus []string) ([]*model.Product, error) {
+	if len(productIDs) == 0 && len(skus) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_ids or skus is required")
+	}
+	if len(productIDs)+len(skus) > maxBatchProducts {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d product_ids and skus per request", maxBatchProducts)
+	}
+	products, err := s.repo.GetCatalogProducts(productIDs, skus)
 	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %v", err)
+	}
+	return products, nil
+}
+
+// GetOrderReservations 返回订单的库存预留记录，订单尚未扣减库存时返回空列表
+func (s *InventoryService) GetOrderReservations(orderID string) ([]*model.Reservation, error) {
+	if orderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	return s.repo.GetReservations(orderID)
+}
+
+// GetProduct 查询单个产品，产品不存在或已删除时返回 NotFound
+func (s *InventoryService) GetProduct(productID int64) (*model.Product, error) {
+	if productID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	product, err := s.repo.GetInventory(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", productID)
+		}
+		return nil, fmt.Errorf("failed to get product: %v", err)
+	}
+	return product, nil
+}
+
+// CreateProduct 新建产品，期初库存 product.Quantity 存放在 warehouseID 仓库。
+// 产品ID和 SKU 不能与现有或已删除的产品重复，未指定 SKU 时以产品ID作为 SKU；
+// 设置了 ParentProductID 的产品是该父产品的一个规格
+func (s *InventoryService) CreateProduct(product *model.Product, warehouseID string) (*model.Product, error) {
+	if product.ProductID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	if err := validateProductName(product.ProductName); err != nil {
+		return nil, err
+	}
+	if product.Price <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "price must be positive")
+	}
+	if product.Quantity < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must not be negative")
+	}
+	if product.ReorderThreshold < 0 {
+		return nil, status.Error(codes.InvalidArgument, "reorder_threshold must not be negative")
+	}
+	if product.SKU == "" {
+		product.SKU = model.DefaultSKU(product.ProductID)
+	}
+	if err := validateSKU(product.SKU, product.ProductID); err != nil {
 		return nil, err
 	}
+	if err := validateAttributes(product.Attributes); err != nil {
+		return nil, err
+	}
+	warehouseID, err := s.resolveWarehouse(warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(product.CategoryID); err != nil {
+		return nil, err
+	}
+	if product.ParentProductID != nil {
+		exists, err := s.repo.ParentProductExists(*product.ParentProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check parent product: %v", err)
+		}
+		if !exists {
+			return nil, status.Errorf(codes.InvalidArgument, "parent product %d not found", *product.ParentProductID)
+		}
+	}
 
-	return results, nil
+	exists, err := s.repo.ProductExists(product.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check product: %v", err)
+	}
+	if exists {
+		return nil, status.Errorf(codes.AlreadyExists, "product %d already exists", product.ProductID)
+	}
+	if exists, err := s.repo.SKUExists(product.SKU); err != nil {
+		return nil, fmt.Errorf("failed to check sku: %v", err)
+	} else if exists {
+		return nil, status.Errorf(codes.AlreadyExists, "sku %s already exists", product.SKU)
+	}
+
+	if err := s.repo.CreateProduct(product, warehouseID, model.MovementActorAdmin); err != nil {
+		// 并发创建同一产品或 SKU 时由唯一索引兜底
+		if exists, _ := s.repo.ProductExists(product.ProductID); exists {
+			return nil, status.Errorf(codes.AlreadyExists, "product %d already exists", product.ProductID)
+		}
+		if exists, _ := s.repo.SKUExists(product.SKU); exists {
+			return nil, status.Errorf(codes.AlreadyExists, "sku %s already exists", product.SKU)
+		}
+		return nil, fmt.Errorf("failed to create product: %v", err)
+	}
+	return product, nil
 }
 
-func (s *InventoryService) UpdateInventory(productID int64, quantity int64) error {
-	err := s.repo.UpdateInventory(productID, quantity)
+// UpdateProduct 更新产品名、单价、补货阈值、分类和规格属性，参数为 nil 的字段保持不变，categoryID 为 0 表示移出分类，
+// attributes 不为 nil 时替换全部规格属性。已下单的订单保存了下单时的价格快照，不受改价影响
+func (s *InventoryService) UpdateProduct(productID int64, name *string, price *int64, reorderThreshold *int64, categoryID *uint, attributes []*model.ProductAttribute) (*model.Product, error) {
+	if productID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	updates := make(map[string]interface{})
+	if name != nil {
+		if err := validateProductName(*name); err != nil {
+			return nil, err
+		}
+		updates["product_name"] = *name
+	}
+	if price != nil {
+		if *price <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "price must be positive")
+		}
+		updates["price"] = *price
+	}
+	if reorderThreshold != nil {
+		if *reorderThreshold < 0 {
+			return nil, status.Error(codes.InvalidArgument, "reorder_threshold must not be negative")
+		}
+		updates["reorder_threshold"] = *reorderThreshold
+	}
+	if categoryID != nil {
+		if *categoryID == 0 {
+			updates["category_id"] = nil
+		} else {
+			if err := s.checkCategory(categoryID); err != nil {
+				return nil, err
+			}
+			updates["category_id"] = *categoryID
+		}
+	}
+	if attributes != nil {
+		if err := validateAttributes(attributes); err != nil {
+			return nil, err
+		}
+	}
+	if len(updates) == 0 && attributes == nil {
+		return nil, status.Error(codes.InvalidArgument, "nothing to update")
+	}
+
+	if err := s.repo.UpdateProduct(productID, updates, attributes); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", productID)
+		}
+		return nil, fmt.Errorf("failed to update product: %v", err)
+	}
+	return s.GetProduct(productID)
+}
+
+// AdjustStock 按增量调整产品在 warehouseID 仓库的在库数量并记录库存流水，调整后该仓库的在库数量不能少于其已预留数量
+func (s *InventoryService) AdjustStock(productID int64, warehouseID string, delta int64, reason string, actor string) (*model.Product, error) {
+	if productID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	if delta == 0 {
+		return nil, status.Error(codes.InvalidArgument, "delta must not be zero")
+	}
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+	if utf8.RuneCountInString(reason) > maxNoteLength {
+		return nil, status.Errorf(codes.InvalidArgument, "reason must be at most %d characters", maxNoteLength)
+	}
+	if actor == "" {
+		actor = model.MovementActorAdmin
+	}
+	warehouseID, err := s.resolveWarehouse(warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjusted, err := s.repo.AdjustStock(productID, warehouseID, delta, reason, actor)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", productID)
+		}
+		return nil, fmt.Errorf("failed to adjust stock: %v", err)
+	}
+	if !adjusted {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"warehouse %s does not have enough unreserved stock of product %d to adjust by %d",
+			warehouseID, productID, delta)
+	}
+	return s.GetProduct(productID)
+}
+
+// DeleteProduct 软删除产品，仍有库存被预留的产品不能删除
+func (s *InventoryService) DeleteProduct(productID int64) error {
+	if productID <= 0 {
+		return status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	deleted, err := s.repo.DeleteProduct(productID)
+	if err != nil {
+		return fmt.Errorf("failed to delete product: %v", err)
+	}
+	if deleted {
+		return nil
+	}
+	product, err := s.GetProduct(productID)
+	if err != nil {
+		return err
+	}
+	return status.Errorf(codes.FailedPrecondition,
+		"product %d still has %d reserved", productID, product.Reserved)
+}
+
+// resolveWarehouse 校验仓库ID，为空时返回默认仓库
+func (s *InventoryService) resolveWarehouse(warehouseID string) (string, error) {
+	if warehouseID == "" {
+		return s.defaultWarehouse, nil
+	}
+	if !s.warehouses[warehouseID] {
+		return "", status.Errorf(codes.InvalidArgument, "unknown warehouse: %s", warehouseID)
+	}
+	return warehouseID, nil
+}
+
+// checkCategory 校验分类存在，categoryID 为 nil 时不校验
+func (s *InventoryService) checkCategory(categoryID *uint) error {
+	if categoryID == nil {
+		return nil
+	}
+	exists, err := s.repo.CategoryExists(*categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to check category: %v", err)
+	}
+	if !exists {
+		return status.Errorf(codes.InvalidArgument, "category %d not found", *categoryID)
+	}
+	return nil
+}
+
+// expandCategories 返回给定分类及其全部子孙分类的ID
+func (s *InventoryService) expandCategories(categoryIDs []uint) ([]uint, error) {
+	categories, err := s.repo.ListCategories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %v", err)
+	}
+	children := make(map[uint][]uint)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	expanded := make([]uint, 0, len(categoryIDs))
+	seen := make(map[uint]bool)
+	queue := append([]uint(nil), categoryIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		expanded = append(expanded, id)
+		queue = append(queue, children[id]...)
+	}
+	return expanded, nil
+}
+
+// validateSKU 校验产品的 SKU。纯数字的 SKU 保留给以产品ID作为 SKU 的默认值，
+// 避免自定义 SKU 与之后创建的产品的默认 SKU 冲突
+func validateSKU(sku string, productID int64) error {
+	if utf8.RuneCountInString(sku) > maxSKULength {
+		return status.Errorf(codes.InvalidArgument, "sku must be at most %d characters", maxSKULength)
+	}
+	if strings.TrimSpace(sku) != sku {
+		return status.Error(codes.InvalidArgument, "sku must not have leading or trailing spaces")
+	}
+	if sku != model.DefaultSKU(productID) && strings.Trim(sku, "0123456789") == "" {
+		return status.Error(codes.InvalidArgument, "sku must not consist only of digits, numeric skus are reserved for product ids")
+	}
+	return nil
+}
+
+func validateAttributes(attributes []*model.ProductAttribute) error {
+	if len(attributes) > maxAttributes {
+		return status.Errorf(codes.InvalidArgument, "at most %d attributes per product", maxAttributes)
+	}
+	for _, attribute := range attributes {
+		if attribute.Key == "" || utf8.RuneCountInString(attribute.Key) > maxAttributeKeyLength {
+			return status.Errorf(codes.InvalidArgument, "attribute key must be 1 to %d characters", maxAttributeKeyLength)
+		}
+		if attribute.Value == "" || utf8.RuneCountInString(attribute.Value) > maxAttributeValueLength {
+			return status.Errorf(codes.InvalidArgument, "value of attribute %s must be 1 to %d characters", attribute.Key, maxAttributeValueLength)
+		}
+	}
+	return nil
+}
+
+func validateProductName(name string) error {
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "product_name is required")
+	}
+	if utf8.RuneCountInString(name) > maxProductNameLength {
+		return status.Errorf(codes.InvalidArgument, "product_name must be at most %d characters", maxProductNameLength)
+	}
+	return nil
+}
+
+// ListStockMovements 按时间倒序分页查询库存流水，返回当前页流水和下一页的游标
+func (s *InventoryService) ListStockMovements(productID int64, orderID string, pageSize int32, pageToken string) ([]*model.StockMovement, string, error) {
+	if productID < 0 {
+		return nil, "", status.Error(codes.InvalidArgument, "product_id must not be negative")
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	beforeID, err := decodeMovementPageToken(pageToken)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	// 多查一条用于判断是否还有下一页
+	movements, err := s.repo.ListStockMovements(productID, orderID, beforeID, int(pageSize)+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list stock movements: %v", err)
+	}
+	if len(movements) <= int(pageSize) {
+		return movements, "", nil
+	}
+
+	movements = movements[:pageSize]
+	return movements, encodeMovementPageToken(movements[len(movements)-1].ID), nil
+}
+
+// CheckStockConsistency 返回在库总数或预留数量与库存流水汇总不一致的产品，结果为空表示账实一致
+func (s *InventoryService) CheckStockConsistency() ([]*model.StockDiscrepancy, error) {
+	discrepancies, err := s.repo.FindStockDiscrepancies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stock consistency: %v", err)
+	}
+	return discrepancies, nil
+}
+
+func encodeMovementPageToken(lastID uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(lastID), 10)))
+}
+
+func decodeMovementPageToken(token string) (uint, error) {
+	if token == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return uint(id), nil
+}
+
+// ImportProducts 按产品ID批量新建或更新产品，返回按行号排序的每行处理结果。
+// 导入中途失败时同时返回已处理行的结果与错误，已提交的批次不会回滚。
+// 未通过校验的行记为 failed 且不做修改，不影响其他行；dryRun 为 true 时只返回将要发生的变化而不写入
+func (s *InventoryService) ImportProducts(rows []*model.ProductImportRow, dryRun bool, actor string) ([]*model.ProductImportResult, error) {
+	if len(rows) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no rows to import")
+	}
+	if len(rows) > maxImportRows {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d rows per import", maxImportRows)
+	}
+	if actor == "" {
+		actor = model.MovementActorAdmin
+	}
+
+	results := make([]*model.ProductImportResult, 0, len(rows))
+	valid := make([]*model.ProductImportRow, 0, len(rows))
+	for _, row := range rows {
+		if err := s.validateImportRow(row); err != nil {
+			results = append(results, &model.ProductImportResult{
+				Line:      row.Line,
+				ProductID: row.ProductID,
+				Action:    model.ProductImportFailed,
+				Error:     status.Convert(err).Message(),
+			})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	var importErr error
+	if len(valid) > 0 {
+		imported, err := s.repo.ImportProducts(valid, importBatchSize, dryRun, actor)
+		if err != nil {
+			// 之前的批次已提交，与失败原因一并返回
+			importErr = fmt.Errorf("failed to import products: %v", err)
+		}
+		results = append(results, imported...)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+	return results, importErr
+}
+
+// validateImportRow 校验导入行的字段并将空仓库替换为默认仓库，行与现有数据的冲突在导入时检查
+func (s *InventoryService) validateImportRow(row *model.ProductImportRow) error {
+	if row.ProductID <= 0 {
+		return status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	if row.ProductName != nil {
+		if err := validateProductName(*row.ProductName); err != nil {
+			return err
+		}
+	}
+	if row.Price != nil && *row.Price <= 0 {
+		return status.Error(codes.InvalidArgument, "price must be positive")
+	}
+	if row.Quantity != nil && *row.Quantity < 0 {
+		return status.Error(codes.InvalidArgument, "quantity must not be negative")
+	}
+	if row.ReorderThreshold != nil && *row.ReorderThreshold < 0 {
+		return status.Error(codes.InvalidArgument, "reorder_threshold must not be negative")
+	}
+	warehouseID, err := s.resolveWarehouse(row.WarehouseID)
 	if err != nil {
 		return err
 	}
+	row.WarehouseID = warehouseID
 	return nil
 }
+
+// ExportProducts 按产品ID顺序分批读取全部产品及其各仓库库存，逐个交给 send，send 返回错误时停止导出
+func (s *InventoryService) ExportProducts(send func(product *model.Product) error) error {
+	var afterID int64
+	for {
+		products, err := s.repo.ListProductsAfter(afterID, exportBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list products: %v", err)
+		}
+		for _, product := range products {
+			if err := send(product); err != nil {
+				return err
+			}
+		}
+		if len(products) < exportBatchSize {
+			return nil
+		}
+		afterID = products[len(products)-1].ProductID
+	}
+}
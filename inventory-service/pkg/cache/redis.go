@@ -0,0 +1,340 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"order-microsystem/inventory-service/pkg/config"
+	"os"
+	"strconv"
+	"time"
+)
+
+// FlashSaleResult 秒杀预扣的结果
+type FlashSaleResult string
+
+const (
+	// FlashSaleNone 订单中没有正在秒杀的产品，按普通订单处理
+	FlashSaleNone FlashSaleResult = "none"
+	// FlashSaleReserved 秒杀库存已在 Redis 中预扣，订单已进入待落库队列
+	FlashSaleReserved FlashSaleResult = "reserved"
+	// FlashSaleSoldOut 秒杀库存不足，订单被拒绝
+	FlashSaleSoldOut FlashSaleResult = "sold_out"
+	// FlashSaleReleased 预扣的秒杀库存已因落库失败或订单取消归还
+	FlashSaleReleased FlashSaleResult = "released"
+	// FlashSaleCancelled 订单在落库前已被取消，落库时不再预留库存并归还预扣的秒杀库存
+	FlashSaleCancelled FlashSaleResult = "cancelled"
+)
+
+const (
+	// flashSalePendingKey 待落库的秒杀订单队列
+	flashSalePendingKey = "flash_sale:pending"
+	// flashSaleProcessingKey 正在落库的秒杀订单，落库完成后移除，进程崩溃后可移回待落库队列
+	flashSaleProcessingKey = "flash_sale:processing"
+	// flashSaleClaimsKey 记录处理中队列每个订单的取出时间与取出的实例，字段为队列中的原始内容
+	flashSaleClaimsKey = "flash_sale:processing:claims"
+	// FlashSaleProcessingTimeout 处理中的订单超过该时长仍未落库时视为取出它的实例已退出，可移回待落库队列
+	FlashSaleProcessingTimeout = 5 * time.Minute
+	// flashSaleResultTTL 订单预扣结果的保留时长，用于识别重复投递的订单
+	flashSaleResultTTL = 24 * time.Hour
+)
+
+func flashSaleStockKey(productID int64) string {
+	return fmt.Sprintf("flash_sale:stock:%d", productID)
+}
+
+func flashSaleSoldKey(productID int64) string {
+	return fmt.Sprintf("flash_sale:sold:%d", productID)
+}
+
+func flashSaleResultKey(orderID string) string {
+	return fmt.Sprintf("flash_sale:order:%s", orderID)
+}
+
+type RedisClient struct {
+	client *redis.Client
+	// worker 标识取出待落库订单的实例，使用主机名，容器重启后保持不变
+	worker string
+}
+
+func NewRedisClient(cfg *config.RedisConfig) *RedisClient {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.Database,
+	})
+
+	worker, err := os.Hostname()
+	if err != nil {
+		worker = fmt.Sprintf("pid-%d", os.Getpid())
+	}
+	return &RedisClient{client: client, worker: worker}
+}
+
+// startFlashSaleScript 秒杀库存不存在时写入预热库存并清零已售数量，返回是否写入成功
+var startFlashSaleScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1])
+redis.call("SET", KEYS[2], 0)
+return 1
+`)
+
+// StartFlashSale 将产品的秒杀库存预热到 Redis，产品已在秒杀中时返回 false
+func (r *RedisClient) StartFlashSale(productID int64, quantity int64) (bool, error) {
+	started, err := startFlashSaleScript.Run(context.Background(), r.client,
+		[]string{flashSaleStockKey(productID), flashSaleSoldKey(productID)}, quantity).Int()
+	return started == 1, err
+}
+
+// stopFlashSaleScript 删除秒杀库存，返回删除前的剩余数量(不存在时为 -1)和已售数量
+var stopFlashSaleScript = redis.NewScript(`
+local remaining = redis.call("GET", KEYS[1])
+redis.call("DEL", KEYS[1])
+local sold = redis.call("GET", KEYS[2])
+return {tonumber(remaining or -1), tonumber(sold or 0)}
+`)
+
+// StopFlashSale 停止产品的秒杀，之后的订单按普通订单处理，返回停止时的剩余数量和已售数量
+func (r *RedisClient) StopFlashSale(productID int64) (remaining int64, sold int64, err error) {
+	values, err := stopFlashSaleScript.Run(context.Background(), r.client,
+		[]string{flashSaleStockKey(productID), flashSaleSoldKey(productID)}).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return values[0], values[1], nil
+}
+
+// tryReserveFlashSaleScript 原子地为订单预扣秒杀库存。
+// KEYS: 结果key, 待落库队列, 然后每个产品依次为 库存key、已售key；
+// ARGV: 结果保留秒数, 订单JSON, 然后每个产品依次为 产品ID、数量。
+// 订单中没有秒杀产品时返回 none；任一秒杀产品库存不足时整单拒绝；
+// 预扣成功时将订单与其秒杀产品数量写入待落库队列。重复调用返回首次的结果
+var tryReserveFlashSaleScript = redis.NewScript(`
+local status = redis.call("GET", KEYS[1])
+if status then
+	return status
+end
+local found = false
+for i = 3, #KEYS, 2 do
+	local stock = redis.call("GET", KEYS[i])
+	if stock then
+		found = true
+		if tonumber(stock) < tonumber(ARGV[i + 1]) then
+			redis.call("SET", KEYS[1], "sold_out", "EX", ARGV[1])
+			return "sold_out"
+		end
+	end
+end
+if not found then
+	return "none"
+end
+local items = {}
+for i = 3, #KEYS, 2 do
+	if redis.call("EXISTS", KEYS[i]) == 1 then
+		redis.call("DECRBY", KEYS[i], ARGV[i + 1])
+		redis.call("INCRBY", KEYS[i + 1], ARGV[i + 1])
+		items[ARGV[i]] = tonumber(ARGV[i + 1])
+	end
+end
+redis.call("SET", KEYS[1], "reserved", "EX", ARGV[1])
+redis.call("RPUSH", KEYS[2], cjson.encode({order = ARGV[2], items = items}))
+return "reserved"
+`)
+
+// TryReserveFlashSale 为订单预扣秒杀库存，quantities 为订单各产品的数量，order 为待落库的订单内容
+func (r *RedisClient) TryReserveFlashSale(orderID string, quantities map[int64]int64, order []byte) (FlashSaleResult, error) {
+	keys := []string{flashSaleResultKey(orderID), flashSalePendingKey}
+	args := []interface{}{int64(flashSaleResultTTL / time.Second), order}
+	for productID, quantity := range quantities {
+		keys = append(keys, flashSaleStockKey(productID), flashSaleSoldKey(productID))
+		args = append(args, productID, quantity)
+	}
+	result, err := tryReserveFlashSaleScript.Run(context.Background(), r.client, keys, args...).Text()
+	if err != nil {
+		return "", err
+	}
+	return FlashSaleResult(result), nil
+}
+
+// releaseFlashSaleScript 归还订单预扣的秒杀库存，秒杀已停止的产品只扣减已售数量。
+// KEYS: 结果key, 然后每个产品依次为 库存key、已售key；ARGV: 每个产品的数量。重复调用不会重复归还
+var releaseFlashSaleScript = redis.NewScript(`
+local status = redis.call("GET", KEYS[1])
+if status ~= "reserved" and status ~= "cancelled" then
+	return 0
+end
+for i = 2, #KEYS, 2 do
+	local quantity = ARGV[i / 2]
+	if redis.call("EXISTS", KEYS[i]) == 1 then
+		redis.call("INCRBY", KEYS[i], quantity)
+	end
+	redis.call("DECRBY", KEYS[i + 1], quantity)
+end
+redis.call("SET", KEYS[1], "released", "KEEPTTL")
+return 1
+`)
+
+// ReleaseFlashSale 归还订单预扣的秒杀库存，items 为订单各秒杀产品的预扣数量
+func (r *RedisClient) ReleaseFlashSale(orderID string, items map[int64]int64) error {
+	keys := []string{flashSaleResultKey(orderID)}
+	args := make([]interface{}, 0, len(items))
+	for productID, quantity := range items {
+		keys = append(keys, flashSaleStockKey(productID), flashSaleSoldKey(productID))
+		args = append(args, quantity)
+	}
+	return releaseFlashSaleScript.Run(context.Background(), r.client, keys, args...).Err()
+}
+
+// cancelFlashSaleOrderScript 将已预扣但尚未落库的订单标记为已取消，返回是否标记成功
+var cancelFlashSaleOrderScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= "reserved" then
+	return 0
+end
+redis.call("SET", KEYS[1], "cancelled", "KEEPTTL")
+return 1
+`)
+
+// CancelFlashSaleOrder 标记订单已取消，仍在待落库队列中的订单落库时会归还预扣的秒杀库存而不再预留
+func (r *RedisClient) CancelFlashSaleOrder(orderID string) error {
+	return cancelFlashSaleOrderScript.Run(context.Background(), r.client, []string{flashSaleResultKey(orderID)}).Err()
+}
+
+// FlashSaleOrderResult 返回订单当前的秒杀预扣结果，没有记录时返回 FlashSaleNone
+func (r *RedisClient) FlashSaleOrderResult(orderID string) (FlashSaleResult, error) {
+	result, err := r.client.Get(context.Background(), flashSaleResultKey(orderID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return FlashSaleNone, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return FlashSaleResult(result), nil
+}
+
+// PendingFlashSaleOrder 待落库的秒杀订单
+type PendingFlashSaleOrder struct {
+	// Order 原始的 order.created 事件内容
+	Order string `json:"order"`
+	// Items 订单中各秒杀产品在 Redis 中预扣的数量，键为产品ID
+	Items map[string]int64 `json:"items"`
+	// raw 队列中的原始内容，用于落库完成后从处理中队列移除
+	raw string
+}
+
+// FlashSaleItems 返回按产品ID索引的秒杀产品预扣数量
+func (o *PendingFlashSaleOrder) FlashSaleItems() (map[int64]int64, error) {
+	items := make(map[int64]int64, len(o.Items))
+	for key, quantity := range o.Items {
+		productID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product id %q: %v", key, err)
+		}
+		items[productID] = quantity
+	}
+	return items, nil
+}
+
+// NextPendingFlashSaleOrder 阻塞至多 timeout 取出下一个待落库的秒杀订单并移入处理中队列，
+// 超时返回 nil。落库完成后应调用 AckPendingFlashSaleOrder
+func (r *RedisClient) NextPendingFlashSaleOrder(ctx context.Context, timeout time.Duration) (*PendingFlashSaleOrder, error) {
+	raw, err := r.client.BLMove(ctx, flashSalePendingKey, flashSaleProcessingKey, "LEFT", "RIGHT", timeout).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 记录取出时间与实例，记录失败时由 RequeueProcessingFlashSaleOrders 补记，不影响落库
+	if err := r.client.HSet(ctx, flashSaleClaimsKey, raw, newFlashSaleClaim(time.Now(), r.worker)).Err(); err != nil {
+		log.Printf("failed to record claim of pending flash sale order: %v", err)
+	}
+	order := &PendingFlashSaleOrder{raw: raw}
+	if err := json.Unmarshal([]byte(raw), order); err != nil {
+		return order, fmt.Errorf("failed to unmarshal pending flash sale order: %v", err)
+	}
+	return order, nil
+}
+
+// AckPendingFlashSaleOrder 将已落库的秒杀订单从处理中队列移除
+func (r *RedisClient) AckPendingFlashSaleOrder(order *PendingFlashSaleOrder) error {
+	pipe := r.client.TxPipeline()
+	pipe.LRem(context.Background(), flashSaleProcessingKey, 1, order.raw)
+	pipe.HDel(context.Background(), flashSaleClaimsKey, order.raw)
+	_, err := pipe.Exec(context.Background())
+	return err
+}
+
+// newFlashSaleClaim 返回处理中订单的取出记录，格式为 "取出时间的Unix秒 实例"
+func newFlashSaleClaim(at time.Time, worker string) string {
+	return fmt.Sprintf("%d %s", at.Unix(), worker)
+}
+
+// requeueProcessingFlashSaleScript 将处理中队列中取出时间早于截止时间或由指定实例取出的订单移回待落库队列。
+// KEYS: 处理中队列, 待落库队列, 取出记录；ARGV: 截止时间的Unix秒, 实例(为空表示不按实例移回), 当前时间的取出记录。
+// 没有取出记录的订单(取出后未能记录)补记为当前时间，超时后再移回。返回移回的数量
+var requeueProcessingFlashSaleScript = redis.NewScript(`
+local requeued = 0
+for _, raw in ipairs(redis.call("LRANGE", KEYS[1], 0, -1)) do
+	local claim = redis.call("HGET", KEYS[3], raw)
+	if not claim then
+		redis.call("HSET", KEYS[3], raw, ARGV[3])
+	else
+		local sep = string.find(claim, " ", 1, true)
+		local claimedAt = tonumber(string.sub(claim, 1, sep - 1))
+		local worker = string.sub(claim, sep + 1)
+		if claimedAt < tonumber(ARGV[1]) or (ARGV[2] ~= "" and worker == ARGV[2]) then
+			redis.call("LREM", KEYS[1], 1, raw)
+			redis.call("HDEL", KEYS[3], raw)
+			redis.call("RPUSH", KEYS[2], raw)
+			requeued = requeued + 1
+		end
+	end
+end
+return requeued
+`)
+
+// RequeueProcessingFlashSaleOrders 将处理中超过 FlashSaleProcessingTimeout 的订单移回待落库队列，
+// includeOwn 为 true 时同时移回本实例此前取出的订单，用于进程重启后恢复未完成的落库，不能在本实例落库期间使用。
+// 落库是幂等的，与其他副本重复处理同一订单不会重复扣减库存
+func (r *RedisClient) RequeueProcessingFlashSaleOrders(includeOwn bool) (int64, error) {
+	now := time.Now()
+	worker := ""
+	if includeOwn {
+		worker = r.worker
+	}
+	return requeueProcessingFlashSaleScript.Run(context.Background(), r.client,
+		[]string{flashSaleProcessingKey, flashSalePendingKey, flashSaleClaimsKey},
+		now.Add(-FlashSaleProcessingTimeout).Unix(), worker, newFlashSaleClaim(now, "")).Int64()
+}
+
+// FlashSaleBacklog 返回待落库和处理中的秒杀订单数量
+func (r *RedisClient) FlashSaleBacklog() (int64, error) {
+	pending, err := r.client.LLen(context.Background(), flashSalePendingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	processing, err := r.client.LLen(context.Background(), flashSaleProcessingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	return pending + processing, nil
+}
+
+// FlashSaleSold 返回产品在 Redis 中记录的秒杀已售数量
+func (r *RedisClient) FlashSaleSold(productID int64) (int64, error) {
+	sold, err := r.client.Get(context.Background(), flashSaleSoldKey(productID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return sold, err
+}
+
+// ClearFlashSale 删除产品对账完成的秒杀计数
+func (r *RedisClient) ClearFlashSale(productID int64) error {
+	return r.client.Del(context.Background(), flashSaleStockKey(productID), flashSaleSoldKey(productID)).Err()
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type ServerConfig struct {
@@ -27,6 +28,13 @@ type RabbitMQConfig struct {
 	Exchange string `mapstructure:"exchange"`
 }
 
+type RedisConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Database int    `mapstructure:"database"`
+	Password string `mapstructure:"password"`
+}
+
 type ConsulConfig struct {
 	Host        string `mapstructure:"host"`
 	Port        int    `mapstructure:"port"`
@@ -40,14 +48,53 @@ type JaegerConfig struct {
 	ServiceName string `mapstructure:"service_name"`
 }
 
+type InventoryConfig struct {
+	// HoldTimeout 库存预留的保留时长，超时仍未支付的预留会被自动归还
+	HoldTimeout time.Duration `mapstructure:"hold_timeout"`
+	// HoldSweepInterval 扫描过期预留的间隔
+	HoldSweepInterval time.Duration `mapstructure:"hold_sweep_interval"`
+	// StockMetricsInterval 刷新库存指标的间隔
+	StockMetricsInterval time.Duration `mapstructure:"stock_metrics_interval"`
+	// Warehouses 参与发货的仓库，未配置时使用单一的 default 仓库
+	Warehouses []WarehouseConfig `mapstructure:"warehouses"`
+	// DefaultWarehouse 未指定仓库的入库操作及引入多仓库前的库存所归属的仓库，默认为第一个仓库
+	DefaultWarehouse string           `mapstructure:"default_warehouse"`
+	Allocation       AllocationConfig `mapstructure:"allocation"`
+}
+
+type WarehouseConfig struct {
+	ID     string `mapstructure:"id"`
+	Region string `mapstructure:"region"`
+	// Priority 越小越优先分配
+	Priority int `mapstructure:"priority"`
+}
+
+type AllocationConfig struct {
+	// Strategy 仓库分配策略: single_warehouse_first、nearest 或 split
+	Strategy string `mapstructure:"strategy"`
+	// Region nearest 策略优先使用的仓库区域
+	Region string `mapstructure:"region"`
+}
+
+// WarehouseIDs 返回全部仓库ID
+func (c *InventoryConfig) WarehouseIDs() []string {
+	ids := make([]string, 0, len(c.Warehouses))
+	for _, warehouse := range c.Warehouses {
+		ids = append(ids, warehouse.ID)
+	}
+	return ids
+}
+
 type Config struct {
 	Server   ServerConfig `mapstructure:"server"`
 	Database struct {
 		MySQL MySQLConfig `mapstructure:"mysql"`
 	} `mapstructure:"database"`
-	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
-	Consul   ConsulConfig   `mapstructure:"consul"`
-	Jaeger   JaegerConfig   `mapstructure:"jaeger"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	RabbitMQ  RabbitMQConfig  `mapstructure:"rabbitmq"`
+	Consul    ConsulConfig    `mapstructure:"consul"`
+	Jaeger    JaegerConfig    `mapstructure:"jaeger"`
+	Inventory InventoryConfig `mapstructure:"inventory"`
 }
 
 func NewConfig(path string) (*Config, error) {
@@ -64,5 +111,23 @@ func NewConfig(path string) (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %v", err)
 	}
+
+	inventory := &config.Inventory
+	if len(inventory.Warehouses) == 0 {
+		inventory.Warehouses = []WarehouseConfig{{ID: "default"}}
+	}
+	if inventory.DefaultWarehouse == "" {
+		inventory.DefaultWarehouse = inventory.Warehouses[0].ID
+	}
+	known := false
+	for _, warehouse := range inventory.Warehouses {
+		if warehouse.ID == "" {
+			return nil, fmt.Errorf("warehouse id is required")
+		}
+		known = known || warehouse.ID == inventory.DefaultWarehouse
+	}
+	if !known {
+		return nil, fmt.Errorf("default warehouse %s is not configured", inventory.DefaultWarehouse)
+	}
 	return &config, nil
 }
@@ -3,11 +3,15 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/rabbitmq/amqp091-go"
 	"log"
+	"order-microsystem/inventory-service/internal/allocation"
+	"order-microsystem/inventory-service/internal/domain/model"
 	"order-microsystem/inventory-service/internal/domain/repository"
+	"order-microsystem/inventory-service/pkg/cache"
 	"order-microsystem/inventory-service/pkg/config"
 	"time"
 )
@@ -17,9 +21,15 @@ type RabbitMQ struct {
 	ch     *amqp091.Channel
 	config *config.RabbitMQConfig
 	repo   *repository.MySQLRepository
+	// holdTimeout 库存预留的保留时长，为 0 表示预留不会过期
+	holdTimeout time.Duration
+	// strategy 为订单选择发货仓库
+	strategy allocation.Strategy
+	// cache 秒杀库存的 Redis 计数
+	cache *cache.RedisClient
 }
 
-func NewRabbitMQ(config *config.RabbitMQConfig, repo *repository.MySQLRepository) (*RabbitMQ, error) {
+func NewRabbitMQ(config *config.RabbitMQConfig, repo *repository.MySQLRepository, holdTimeout time.Duration, strategy allocation.Strategy, cache *cache.RedisClient) (*RabbitMQ, error) {
 	url := fmt.Sprintf("amqp://%s:%s@%s:%d", config.Username, config.Password, config.Host, config.Port)
 	var conn *amqp091.Connection
 	var err error
@@ -54,10 +64,13 @@ func NewRabbitMQ(config *config.RabbitMQConfig, repo *repository.MySQLRepository
 	}
 
 	return &RabbitMQ{
-		conn:   conn,
-		ch:     channel,
-		config: config,
-		repo:   repo,
+		conn:        conn,
+		ch:          channel,
+		config:      config,
+		repo:        repo,
+		holdTimeout: holdTimeout,
+		strategy:    strategy,
+		cache:       cache,
 	}, nil
 }
 
@@ -68,111 +81,578 @@ func (rmq *RabbitMQ) Close() error {
 	return rmq.conn.Close()
 }
 
-func (rmq *RabbitMQ) ConsumeOrderCreated() {
-	// 声明一个匿名队列
+// queuePrefix 本服务所有消费队列名称的前缀
+const queuePrefix = "inventory-service."
+
+// consume 声明持久化的具名队列并绑定路由键后开始消费。服务停机期间投递的消息会保留在队列中，
+// 多个副本共享同一队列时每条消息只会被其中一个副本处理
+func (rmq *RabbitMQ) consume(name string, routingKeys ...string) (<-chan amqp091.Delivery, error) {
 	q, err := rmq.ch.QueueDeclare(
-		"",
-		false,
-		false,
-		true,
-		false,
-		nil,
+		queuePrefix+name, // 队列名称
+		true,             // 持久化
+		false,            // 不自动删除
+		false,            // 非排他性
+		false,            // 不等待
+		nil,              // 额外参数
 	)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("failed to declare queue: %v", err)
 	}
 
-	err = rmq.ch.QueueBind(
-		q.Name,
-		"order.created",
-		rmq.config.Exchange,
-		false,
-		nil,
-	)
+	for _, routingKey := range routingKeys {
+		if err := rmq.ch.QueueBind(q.Name, routingKey, rmq.config.Exchange, false, nil); err != nil {
+			return nil, fmt.Errorf("failed to bind queue: %v", err)
+		}
+	}
+
+	return rmq.ch.Consume(q.Name, "", false, false, false, false, nil)
+}
+
+// orderCreatedEvent 是 order.created 事件中库存服务关心的字段
+type orderCreatedEvent struct {
+	EventType string    `json:"event_type"`
+	OrderID   uuid.UUID `json:"order_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Status    string    `json:"status"`
+	Products  []struct {
+		ProductID int64 `json:"product_id"`
+		Quantity  int64 `json:"quantity"`
+		Price     int64 `json:"price"`
+	} `json:"products"`
+	TotalPrice int64  `json:"total_price"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// Shortage 描述某个产品的库存缺口
+type Shortage struct {
+	ProductID int64 `json:"product_id"`
+	Requested int64 `json:"requested"`
+	Available int64 `json:"available"`
+}
+
+// errInsufficientStock 用于在库存不足时回滚整单的扣减
+var errInsufficientStock = errors.New("insufficient stock")
+
+// ConsumeOrderCreated 消费订单创建事件，按分配策略选择发货仓库后在一个事务中为订单的全部商品预留库存：
+// 全部预留成功时发布携带仓库分配结果的 inventory.locked，任一商品可用库存不足则整单不预留并发布 inventory.insufficient。
+// 包含秒杀产品的订单先在 Redis 中预扣秒杀库存，预扣成功的订单由 PersistFlashSaleOrders 异步落库，售罄的订单不访问库存表直接拒绝
+func (rmq *RabbitMQ) ConsumeOrderCreated() {
+	msgs, err := rmq.consume("order.created", "order.created")
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	msgs, err := rmq.ch.Consume(
-		q.Name,
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
-
 	for msg := range msgs {
-		var receive_msg struct {
-			EventType string    `json:"event_type"`
-			OrderID   uuid.UUID `json:"order_id"`
-			UserID    uuid.UUID `json:"user_id"`
-			Status    string    `json:"status"`
-			Products  []struct {
-				ProductID int64 `json:"product_id"`
-				Quantity  int64 `json:"quantity"`
-				Price     int64 `json:"price"`
-			} `json:"products"`
-			TotalPrice int64  `json:"total_price"`
-			CreatedAt  string `json:"created_at"`
-		}
+		var receive_msg orderCreatedEvent
 		if err := json.Unmarshal(msg.Body, &receive_msg); err != nil {
-			log.Fatal(err.Error())
+			log.Printf("failed to unmarshal order created event: %v", err)
+			msg.Nack(false, false)
+			continue
+		}
+
+		if err := rmq.handleOrderCreated(&receive_msg, msg.Body); err != nil {
+			log.Printf("failed to lock inventory for order %s: %v", receive_msg.OrderID, err)
 			msg.Nack(false, true)
 			continue
 		}
+		msg.Ack(false)
+	}
+}
+
+// handleOrderCreated 订单包含秒杀产品时在 Redis 中预扣秒杀库存，超出秒杀库存的订单在访问 MySQL 前即被拒绝；否则直接在 MySQL 中预留库存。
+// 已取消的订单由 reserveOrder 在预留事务中跳过，已预扣秒杀库存的订单在落库时跳过并归还秒杀库存
+func (rmq *RabbitMQ) handleOrderCreated(order *orderCreatedEvent, body []byte) error {
+	quantities, productIDs := orderQuantities(order)
+	result, err := rmq.cache.TryReserveFlashSale(order.OrderID.String(), quantities, body)
+	if err != nil {
+		// Redis 不可用时，不涉及秒杀产品的订单直接在 MySQL 中预留，避免秒杀之外的订单也被阻塞
+		open, checkErr := rmq.repo.HasOpenFlashSale(productIDs)
+		if checkErr != nil || open {
+			return fmt.Errorf("failed to reserve flash sale stock: %v", err)
+		}
+		log.Printf("failed to reserve flash sale stock for order %s, reserving in mysql: %v", order.OrderID, err)
+		result = cache.FlashSaleNone
+	}
+
+	switch result {
+	case cache.FlashSaleNone:
+		_, err := rmq.reserveOrder(order, nil)
+		return err
+	case cache.FlashSaleSoldOut:
+		// 秒杀库存不足的订单不写入预留记录，重复投递时由 Redis 中保留的结果再次拒绝
+		event, err := NewInventoryInsufficientEvent(order.OrderID, order.UserID, nil, InsufficientReasonSoldOut)
+		if err != nil {
+			return err
+		}
+		return rmq.repo.AddOutboxEvent(event)
+	default:
+		// 已预扣的订单等待落库，重复投递无需处理
+		return nil
+	}
+}
+
+// orderQuantities 按商品合并订单中的数量，同一商品可能在订单中出现多次，返回的商品ID保持订单中的顺序
+func orderQuantities(order *orderCreatedEvent) (map[int64]int64, []int64) {
+	quantities := make(map[int64]int64)
+	productIDs := make([]int64, 0, len(order.Products))
+	for _, item := range order.Products {
+		if _, ok := quantities[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+	return quantities, productIDs
+}
+
+// reserveOrder 为订单预留库存并写入结果事件，返回订单的库存是否已预留，重复投递的订单与已取消的订单不会预留。
+// flashItems 为订单在 Redis 中预扣的秒杀数量，预留成功时在同一事务中累加到对应秒杀的已落库数量
+func (rmq *RabbitMQ) reserveOrder(order *orderCreatedEvent, flashItems map[int64]int64) (bool, error) {
+	orderID := order.OrderID.String()
+	quantities, productIDs := orderQuantities(order)
+
+	var expiresAt *time.Time
+	if rmq.holdTimeout > 0 {
+		expiry := time.Now().Add(rmq.holdTimeout)
+		expiresAt = &expiry
+	}
+
+	var shortages []Shortage
+	reserved := true
+	// 库存预留、预留记录与 inventory.locked 事件在同一事务中写入
+	err := rmq.repo.Transaction(func(txRepo *repository.MySQLRepository) error {
+		// 取消事件先于 order.created 处理时，取消时没有可归还的预留，此时也不再预留
+		cancelled, err := txRepo.IsOrderCancelled(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to check order cancellation: %v", err)
+		}
+		if cancelled {
+			reserved = false
+			return nil
+		}
 
-		for _, item := range receive_msg.Products {
-			source, err := rmq.repo.GetInventory(item.ProductID)
+		// 重复投递（如订单服务对账时重新发布）的订单不再扣减库存，仅重新发布此前的处理结果
+		existing, err := txRepo.GetReservations(orderID)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			var event *model.OutboxEvent
+			reserved = existing[0].Status != model.ReservationStatusRejected
+			switch existing[0].Status {
+			case model.ReservationStatusLocked:
+				allocations := make([]allocation.Allocation, 0, len(existing))
+				for _, reservation := range existing {
+					allocations = append(allocations, allocation.Allocation{
+						ProductID:   reservation.ProductID,
+						WarehouseID: reservation.WarehouseID,
+						Quantity:    reservation.Quantity,
+					})
+				}
+				event, err = NewInventoryLockedEvent(order.OrderID, order.UserID, order.TotalPrice, allocations)
+			case model.ReservationStatusRejected:
+				event, err = NewInventoryInsufficientEvent(order.OrderID, order.UserID, nil, InsufficientReasonStock)
+			default:
+				return nil
+			}
 			if err != nil {
-				log.Fatalf("ProductID not exists: %v", err)
-				continue
+				return err
+			}
+			return txRepo.AddOutboxEvent(event)
+		}
+
+		// 锁定各仓库库存后再分配，分配结果在事务内不会因并发预留而失效
+		stocks, err := txRepo.LockWarehouseStocks(productIDs)
+		if err != nil {
+			return fmt.Errorf("failed to lock warehouse stocks: %v", err)
+		}
+		available := make(allocation.Available)
+		for _, stock := range stocks {
+			if available[stock.ProductID] == nil {
+				available[stock.ProductID] = make(map[string]int64)
+			}
+			available[stock.ProductID][stock.WarehouseID] = stock.Available()
+		}
+		items := make([]allocation.Item, 0, len(productIDs))
+		for _, productID := range productIDs {
+			items = append(items, allocation.Item{ProductID: productID, Quantity: quantities[productID]})
+		}
+		allocations := rmq.strategy.Allocate(items, available)
+
+		allocated := make(map[int64]int64)
+		for _, a := range allocations {
+			allocated[a.ProductID] += a.Quantity
+		}
+		for _, productID := range productIDs {
+			if allocated[productID] < quantities[productID] {
+				shortages = append(shortages, Shortage{ProductID: productID, Requested: quantities[productID]})
+			}
+		}
+		if len(shortages) > 0 {
+			return errInsufficientStock
+		}
+
+		for _, a := range allocations {
+			ok, err := txRepo.ReserveStock(a.ProductID, a.WarehouseID, a.Quantity, orderID)
+			if err != nil {
+				return fmt.Errorf("failed to reserve stock of product %d in warehouse %s: %v", a.ProductID, a.WarehouseID, err)
+			}
+			if !ok {
+				// 产品在分配后被删除
+				shortages = append(shortages, Shortage{ProductID: a.ProductID, Requested: quantities[a.ProductID]})
+				return errInsufficientStock
 			}
-			quantity := source.Quantity - item.Quantity
-			err = rmq.repo.UpdateInventory(item.ProductID, quantity)
+			// 记录本订单在该仓库预留的库存，支付完成时提交，取消或超时时归还
+			err = txRepo.CreateReservation(&model.Reservation{
+				OrderID:     orderID,
+				ProductID:   a.ProductID,
+				WarehouseID: a.WarehouseID,
+				Quantity:    a.Quantity,
+				Status:      model.ReservationStatusLocked,
+				ExpiresAt:   expiresAt,
+			})
 			if err != nil {
-				log.Fatalf("Update inventory failed: %v", err)
+				return fmt.Errorf("failed to record reservation: %v", err)
+			}
+		}
+
+		// 本次预留使可用库存跌破补货阈值的产品发布 inventory.low，已低于阈值的产品不重复提醒
+		products, err := txRepo.GetProducts(productIDs)
+		if err != nil {
+			return err
+		}
+		for _, product := range products {
+			if !product.BelowThreshold() || product.Available()+quantities[product.ProductID] < product.ReorderThreshold {
 				continue
 			}
+			event, err := NewInventoryLowEvent(order.OrderID, product)
+			if err != nil {
+				return err
+			}
+			if err := txRepo.AddOutboxEvent(event); err != nil {
+				return err
+			}
 		}
 
-		fmt.Println("totalprice: ", receive_msg.TotalPrice)
-		rmq.PublishInventoryLocked(receive_msg.OrderID, receive_msg.UserID, receive_msg.TotalPrice)
-		msg.Ack(false)
+		for productID, quantity := range flashItems {
+			if err := txRepo.AddFlashSalePersisted(productID, quantity); err != nil {
+				return fmt.Errorf("failed to record persisted flash sale of product %d: %v", productID, err)
+			}
+		}
+
+		event, err := NewInventoryLockedEvent(order.OrderID, order.UserID, order.TotalPrice, allocations)
+		if err != nil {
+			return err
+		}
+		return txRepo.AddOutboxEvent(event)
+	})
+	if !errors.Is(err, errInsufficientStock) {
+		return reserved, err
+	}
+
+	// 整单预留已回滚，记录拒绝结果并发布 inventory.insufficient
+	return false, rmq.repo.Transaction(func(txRepo *repository.MySQLRepository) error {
+		products, err := txRepo.GetProducts(productIDs)
+		if err != nil {
+			return err
+		}
+		available := make(map[int64]int64, len(products))
+		for _, product := range products {
+			available[product.ProductID] = product.Available()
+		}
+		for i := range shortages {
+			shortages[i].Available = available[shortages[i].ProductID]
+		}
+
+		for _, productID := range productIDs {
+			err := txRepo.CreateReservation(&model.Reservation{
+				OrderID:   orderID,
+				ProductID: productID,
+				Quantity:  quantities[productID],
+				Status:    model.ReservationStatusRejected,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record rejected reservation: %v", err)
+			}
+		}
+
+		event, err := NewInventoryInsufficientEvent(order.OrderID, order.UserID, shortages, InsufficientReasonStock)
+		if err != nil {
+			return err
+		}
+		return txRepo.AddOutboxEvent(event)
+	})
+}
+
+// PersistFlashSaleOrders 持续将 Redis 中预扣成功的秒杀订单写入 MySQL 库存预留，直到 ctx 被取消。
+// 落库时库存不足的订单归还预扣的秒杀库存
+func (rmq *RabbitMQ) PersistFlashSaleOrders(ctx context.Context) {
+	// 本实例上次退出时未完成落库的订单重新排队
+	if _, err := rmq.cache.RequeueProcessingFlashSaleOrders(true); err != nil {
+		log.Printf("failed to requeue processing flash sale orders: %v", err)
+	}
+
+	lastRequeue := time.Now()
+	for ctx.Err() == nil {
+		// 定期将已退出的其他实例未完成落库的订单重新排队
+		if time.Since(lastRequeue) >= cache.FlashSaleProcessingTimeout/2 {
+			lastRequeue = time.Now()
+			if requeued, err := rmq.cache.RequeueProcessingFlashSaleOrders(false); err != nil {
+				log.Printf("failed to requeue stale flash sale orders: %v", err)
+			} else if requeued > 0 {
+				log.Printf("requeued %d stale flash sale orders", requeued)
+			}
+		}
+
+		pending, err := rmq.cache.NextPendingFlashSaleOrder(ctx, time.Second)
+		if err != nil && pending == nil {
+			if ctx.Err() == nil {
+				log.Printf("failed to fetch pending flash sale order: %v", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+		if pending == nil {
+			continue
+		}
+
+		for ctx.Err() == nil {
+			if err == nil {
+				err = rmq.persistFlashSaleOrder(pending)
+			} else {
+				// 无法解析的订单不会再成功落库，直接丢弃
+				log.Printf("dropping malformed flash sale order: %v", err)
+				err = nil
+			}
+			if err == nil {
+				err = rmq.cache.AckPendingFlashSaleOrder(pending)
+			}
+			if err == nil {
+				break
+			}
+			log.Printf("failed to persist flash sale order: %v", err)
+			time.Sleep(time.Second)
+		}
+	}
+}
+
+// persistFlashSaleOrder 为预扣成功的秒杀订单预留库存，订单已取消或预留失败时归还 Redis 中预扣的秒杀库存
+func (rmq *RabbitMQ) persistFlashSaleOrder(pending *cache.PendingFlashSaleOrder) error {
+	items, err := pending.FlashSaleItems()
+	if err != nil {
+		return err
+	}
+	var order orderCreatedEvent
+	if err := json.Unmarshal([]byte(pending.Order), &order); err != nil {
+		return fmt.Errorf("failed to unmarshal order created event: %v", err)
+	}
+
+	result, err := rmq.cache.FlashSaleOrderResult(order.OrderID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get flash sale result of order %s: %v", order.OrderID, err)
+	}
+	reserved := false
+	if result != cache.FlashSaleCancelled {
+		reserved, err = rmq.reserveOrder(&order, items)
+		if err != nil {
+			return fmt.Errorf("failed to lock inventory for order %s: %v", order.OrderID, err)
+		}
+	}
+	if !reserved {
+		if err := rmq.cache.ReleaseFlashSale(order.OrderID.String(), items); err != nil {
+			return fmt.Errorf("failed to release flash sale stock of order %s: %v", order.OrderID, err)
+		}
+	}
+	return nil
+}
+
+const (
+	// InsufficientReasonStock 可用库存不足
+	InsufficientReasonStock = "insufficient_stock"
+	// InsufficientReasonSoldOut 秒杀库存已售罄
+	InsufficientReasonSoldOut = "sold_out"
+)
+
+// NewInventoryInsufficientEvent 构建库存不足事件，订单服务据此将订单置为失败，reason 区分普通库存不足与秒杀售罄
+func NewInventoryInsufficientEvent(orderID uuid.UUID, userID uuid.UUID, shortages []Shortage, reason string) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type": "inventory_insufficient",
+		"order_id":   orderID,
+		"user_id":    userID,
+		"shortages":  shortages,
+		"reason":     reason,
 	}
+
+	return newOutboxEvent("inventory.insufficient", event)
 }
 
-func (rmq *RabbitMQ) PublishInventoryLocked(orderID uuid.UUID, userID uuid.UUID, totalPrice int64) error {
+// NewInventoryLowEvent 构建库存不足补货提醒事件，orderID 为使可用库存跌破阈值的订单
+func NewInventoryLowEvent(orderID uuid.UUID, product *model.Product) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type":        "inventory_low",
+		"order_id":          orderID,
+		"product_id":        product.ProductID,
+		"product_name":      product.ProductName,
+		"available":         product.Available(),
+		"reorder_threshold": product.ReorderThreshold,
+	}
+
+	return newOutboxEvent("inventory.low", event)
+}
+
+// NewInventoryLockedEvent 构建库存锁定事件，支付服务据此创建支付，履约方据 allocations 从对应仓库发货
+func NewInventoryLockedEvent(orderID uuid.UUID, userID uuid.UUID, totalPrice int64, allocations []allocation.Allocation) (*model.OutboxEvent, error) {
 	event := map[string]interface{}{
 		"event_type":  "inventory_locked",
 		"order_id":    orderID,
 		"user_id":     userID,
 		"total_price": totalPrice,
+		"allocations": allocations,
 	}
 
-	// 序列化
-	body, err := json.Marshal(event)
+	return newOutboxEvent("inventory.locked", event)
+}
+
+// ConsumePaymentCompleted 消费支付完成事件，提交订单预留的库存
+func (rmq *RabbitMQ) ConsumePaymentCompleted() {
+	msgs, err := rmq.consume("payment.completed", "payment.completed")
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %v", err)
+		log.Fatal(err.Error())
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	for msg := range msgs {
+		var receive_msg struct {
+			EventType string    `json:"event_type"`
+			OrderID   uuid.UUID `json:"order_id"`
+		}
+		if err := json.Unmarshal(msg.Body, &receive_msg); err != nil {
+			log.Printf("failed to unmarshal payment completed event: %v", err)
+			msg.Nack(false, false)
+			continue
+		}
 
-	err = rmq.ch.PublishWithContext(ctx,
-		rmq.config.Exchange,
-		"inventory.locked",
-		false,
-		false,
-		amqp091.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
-	)
+		committed, err := rmq.repo.CommitReservations(receive_msg.OrderID.String())
+		if err != nil {
+			log.Printf("failed to commit reservations of order %s: %v", receive_msg.OrderID, err)
+			msg.Nack(false, true)
+			continue
+		}
+		if !committed {
+			if err := rmq.handleUncommittedPayment(receive_msg.OrderID); err != nil {
+				log.Printf("failed to handle uncommitted payment of order %s: %v", receive_msg.OrderID, err)
+				msg.Nack(false, true)
+				continue
+			}
+		}
+		msg.Ack(false)
+	}
+}
+
+// handleUncommittedPayment 处理支付完成时没有可提交预留的订单：预留已提交说明是重复投递，直接忽略；
+// 否则预留已过期归还或从未成功预留，发布 inventory.commit_failed 由订单服务取消订单并退款
+func (rmq *RabbitMQ) handleUncommittedPayment(orderID uuid.UUID) error {
+	reservations, err := rmq.repo.GetReservations(orderID.String())
 	if err != nil {
-		return fmt.Errorf("failed to publish message: %v", err)
+		return err
+	}
+	for _, reservation := range reservations {
+		if reservation.Status == model.ReservationStatusCommitted {
+			return nil
+		}
 	}
 
-	return nil
+	log.Printf("no held reservations to commit for paid order %s, requesting cancellation", orderID)
+	event, err := NewInventoryCommitFailedEvent(orderID, "reservation expired before payment completed")
+	if err != nil {
+		return err
+	}
+	return rmq.repo.AddOutboxEvent(event)
+}
+
+// NewInventoryCommitFailedEvent 构建库存提交失败事件，订单已支付但预留的库存已不存在，订单服务据此取消订单并退款
+func NewInventoryCommitFailedEvent(orderID uuid.UUID, reason string) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type": "inventory_commit_failed",
+		"order_id":   orderID,
+		"reason":     reason,
+	}
+
+	return newOutboxEvent("inventory.commit_failed", event)
+}
+
+// ConsumeOrderCancelled 消费订单取消(order.cancelled)、订单超时(order.expired)与支付失败(payment.failed)事件，
+// 归还订单锁定的库存并回传 inventory.released 确认
+func (rmq *RabbitMQ) ConsumeOrderCancelled() {
+	// 超时取消、支付失败的订单与主动取消的订单按相同方式处理
+	msgs, err := rmq.consume("order.cancellations", "order.cancelled", "order.expired", "payment.failed")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	for msg := range msgs {
+		var receive_msg struct {
+			EventType string    `json:"event_type"`
+			OrderID   uuid.UUID `json:"order_id"`
+		}
+		if err := json.Unmarshal(msg.Body, &receive_msg); err != nil {
+			log.Printf("failed to unmarshal %s event: %v", msg.RoutingKey, err)
+			msg.Nack(false, false)
+			continue
+		}
+
+		// 仍在待落库队列中的秒杀订单落库时直接归还预扣的秒杀库存；
+		// Redis 不可用时由下面的取消标记保证落库时不再预留，不阻塞取消
+		if err := rmq.cache.CancelFlashSaleOrder(receive_msg.OrderID.String()); err != nil {
+			log.Printf("failed to mark flash sale order %s cancelled: %v", receive_msg.OrderID, err)
+		}
+
+		// 取消标记、库存归还与 inventory.released 确认在同一事务中写入，
+		// 取消标记使之后才到达的 order.created 不再为该订单预留库存
+		err := rmq.repo.Transaction(func(txRepo *repository.MySQLRepository) error {
+			if err := txRepo.MarkOrderCancelled(receive_msg.OrderID.String()); err != nil {
+				return fmt.Errorf("failed to mark order cancelled: %v", err)
+			}
+			released, err := txRepo.ReleaseReservations(receive_msg.OrderID.String())
+			if err != nil {
+				return err
+			}
+			event, err := NewInventoryReleasedEvent(receive_msg.OrderID, released)
+			if err != nil {
+				return err
+			}
+			return txRepo.AddOutboxEvent(event)
+		})
+		if err != nil {
+			log.Printf("failed to release inventory for order %s: %v", receive_msg.OrderID, err)
+			msg.Nack(false, true)
+			continue
+		}
+		msg.Ack(false)
+	}
+}
+
+// NewInventoryReleasedEvent 构建库存归还确认事件，released 为 false 表示该订单没有需要归还的库存
+func NewInventoryReleasedEvent(orderID uuid.UUID, released bool) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type": "inventory_released",
+		"order_id":   orderID,
+		"released":   released,
+	}
+
+	return newOutboxEvent("inventory.released", event)
+}
+
+// newOutboxEvent 将事件序列化为 JSON 并生成唯一的事件ID，该ID会作为消息的 MessageId 投递
+func newOutboxEvent(routingKey string, event interface{}) (*model.OutboxEvent, error) {
+	// 序列化
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	return &model.OutboxEvent{
+		ID:         uuid.New().String(),
+		RoutingKey: routingKey,
+		Payload:    string(body),
+		CreatedAt:  time.Now(),
+	}, nil
 }
@@ -16,4 +16,16 @@ var (
 		Help:    "gRPC request duration",
 		Buckets: []float64{0.1, 0.3, 0.5, 1.0, 2.5, 5.0},
 	}, []string{"service", "method", "code"}) // 3个标签
+
+	// Stock 各产品当前的库存，state 为 quantity、reserved 或 available
+	Stock = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "inventory_stock",
+		Help: "Current stock per product",
+	}, []string{"product_id", "state"})
+
+	// ReorderThreshold 各产品的补货阈值，为 0 表示不提醒
+	ReorderThreshold = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "inventory_reorder_threshold",
+		Help: "Reorder threshold per product",
+	}, []string{"product_id"})
 )
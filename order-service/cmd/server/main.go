@@ -6,6 +6,8 @@ import (
 	"log"
 	"order-microsystem/order-service/internal/controller"
 	"order-microsystem/order-service/internal/domain/repository/mongodb"
+	"order-microsystem/order-service/internal/proxy"
+	"order-microsystem/order-service/internal/scheduler"
 	"order-microsystem/order-service/internal/server"
 	"order-microsystem/order-service/internal/service"
 	"order-microsystem/order-service/pkg/cache"
@@ -49,6 +51,9 @@ func main() {
 		cfg.Database.Mongo.Password,
 		cfg.Database.Mongo.Host,
 		cfg.Database.Mongo.Port)
+	if cfg.Database.Mongo.ReplicaSet != "" {
+		uri += "/?replicaSet=" + cfg.Database.Mongo.ReplicaSet
+	}
 	// 连接 MongoDB 数据库
 	mongoClient, err := database.NewMongoDB(uri)
 	if err != nil {
@@ -64,11 +69,20 @@ func main() {
 	}()
 	// 获取指定名称的 MongoDB 数据库实例
 	db := mongoClient.Client.Database(cfg.Database.Mongo.Database)
+	// 创建发件箱仓库实例，领域事件先写入发件箱再由 relay 投递
+	outboxRepo := mongodb.NewOutboxRepository(db)
+	if err := outboxRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("failed to create outbox indexes: %v", err)
+	}
 	// 创建订单仓库实例，用于操作 MongoDB 中的订单数据
-	orderRepo := mongodb.NewOrderRepository(db)
+	orderRepo := mongodb.NewOrderRepository(db, outboxRepo)
+	// 创建订单列表查询所需的索引
+	if err := orderRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("failed to create order indexes: %v", err)
+	}
 
-	// 连接 RabbitMQ 消息队列，传入配置信息和订单仓库实例
-	rabbitmq, err := messaging.NewRabbitMQ(&cfg.RabbitMQ, orderRepo)
+	// 连接 RabbitMQ 消息队列，传入配置信息
+	rabbitmq, err := messaging.NewRabbitMQ(&cfg.RabbitMQ)
 	if err != nil {
 		// 若连接 RabbitMQ 失败，记录错误信息并终止程序
 		log.Fatalf("failed to connect rabbitmq: %v", err)
@@ -80,14 +94,47 @@ func main() {
 			log.Fatalf("failed to close rabbitmq: %v", err)
 		}
 	}()
-	// 启动一个 goroutine 来消费支付完成的消息
-	go rabbitmq.ConsumePaymentCompleted()
+
+	// 启动发件箱 relay，将已落库的领域事件投递到 RabbitMQ
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go messaging.NewOutboxRelay(rabbitmq, outboxRepo).Run(relayCtx)
 
 	// 创建 Redis 客户端实例，用于缓存操作
 	redisClient := cache.NewRedisClient(&cfg.Redis)
 
-	// 初始化服务层，传入订单仓库、RabbitMQ 实例和 Redis 客户端
-	orderService := service.NewOrderService(orderRepo, rabbitmq, redisClient)
+	// 创建库存服务、支付服务的 gRPC 代理，首次调用时通过 Consul 发现服务地址
+	inventoryProxy := proxy.NewInventoryProxy(cfg)
+	defer inventoryProxy.Close()
+	paymentProxy := proxy.NewPaymentProxy(cfg)
+	defer paymentProxy.Close()
+
+	// 初始化服务层，传入订单仓库、Redis 客户端、产品目录和支付超时时长
+	orderService := service.NewOrderService(orderRepo, redisClient, inventoryProxy, cfg.Order.PaymentTimeout)
+
+	// 启动超时订单扫描，多副本之间通过 Redis 租约保证同一时刻只有一个副本在扫描
+	if cfg.Order.PaymentTimeout > 0 {
+		sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+		defer stopSweeper()
+		go scheduler.NewExpirySweeper(orderService, redisClient, cfg.Order.ExpirySweepInterval).Run(sweeperCtx)
+	}
+
+	// 启动对账任务，核对停留在非终态过久的订单并推进订单或补发丢失的事件
+	reconcilerCtx, stopReconciler := context.WithCancel(context.Background())
+	defer stopReconciler()
+	go scheduler.NewReconciler(orderService, inventoryProxy, paymentProxy, redisClient,
+		cfg.Order.ReconcileInterval, cfg.Order.ReconcileAfter).Run(reconcilerCtx)
+
+	// 启动一个 goroutine 来消费支付完成的消息
+	go rabbitmq.ConsumePaymentCompleted(orderService)
+	// 启动一个 goroutine 来消费支付失败的消息，将订单置为支付失败
+	go rabbitmq.ConsumePaymentFailed(orderService)
+	// 启动一个 goroutine 来消费库存不足的消息，将订单置为失败
+	go rabbitmq.ConsumeInventoryInsufficient(orderService)
+	// 启动一个 goroutine 来消费库存提交失败的消息，取消已支付但库存预留已过期的订单
+	go rabbitmq.ConsumeInventoryCommitFailed(orderService)
+	// 启动一个 goroutine 来消费取消订单的补偿确认消息
+	go rabbitmq.ConsumeCompensations(orderService)
 
 	// 初始化控制器层，传入订单服务实例
 	ordercontroller := controller.NewOrderController(orderService)
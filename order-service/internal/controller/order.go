@@ -4,12 +4,16 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"log"
 	"order-microsystem/order-service/internal/domain/model"
 	"order-microsystem/order-service/internal/service"
 	pb "order-microsystem/order-service/pkg/proto/order"
 )
 
+// idempotencyKeyMetadata 携带幂等键的 gRPC 元数据名称
+const idempotencyKeyMetadata = "idempotency-key"
+
 type OrderController struct {
 	pb.UnimplementedOrderServiceServer
 	svc *service.OrderService
@@ -29,28 +33,29 @@ func (s *OrderController) CreateOrder(ctx context.Context, req *pb.CreateOrderRe
 	for _, item := range req.Items {
 		items = append(items, model.OrderItem{
 			ProductID: item.ProductId,
+			SKU:       item.Sku,
 			Quantity:  item.Quantity,
 			Price:     item.Price,
 		})
 	}
 
+	// 网关通过 gRPC 元数据透传客户端的 Idempotency-Key
+	var idempotencyKey string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+			idempotencyKey = values[0]
+		}
+	}
+
 	// 调用服务层
-	createdOrder, err := s.svc.CreateOrder(ctx, convertToUUID(req.CustomerId), items)
+	createdOrder, err := s.svc.CreateOrder(ctx, convertToUUID(req.CustomerId), items, idempotencyKey)
 	if err != nil {
 		return nil, err
 	}
 
-	// 转换响应
+	// 转换响应，商品单价与名称以库存服务目录为准
 	return &pb.CreateOrderResponse{
-		Order: &pb.Order{
-			Id:         createdOrder.ID.String(),
-			CustomerId: createdOrder.UserID.String(),
-			Items:      req.Items, // 保持原始items
-			TotalPrice: createdOrder.TotalPrice,
-			Status:     string(createdOrder.Status),
-			CreatedAt:  createdOrder.CreatedAt,
-			UpdatedAt:  createdOrder.UpdatedAt,
-		},
+		Order: convertToProtoOrder(createdOrder),
 	}, nil
 }
 
@@ -62,46 +67,100 @@ func (s *OrderController) GetOrder(ctx context.Context, req *pb.GetOrderRequest)
 
 	// 转换响应
 	return &pb.GetOrderResponse{
-		Order: &pb.Order{
-			Id:         order.ID.String(),
-			CustomerId: order.UserID.String(),
-			Items:      convertToProtoItems(order.Items),
-			TotalPrice: order.TotalPrice,
-			Status:     string(order.Status),
-			CreatedAt:  order.CreatedAt,
-			UpdatedAt:  order.UpdatedAt,
-		},
+		Order: convertToProtoOrder(order),
 	}, nil
 }
 
 func (s *OrderController) UpdateOrder(ctx context.Context, req *pb.UpdateOrderRequest) (*pb.UpdateOrderResponse, error) {
-	updatedOrder, err := s.svc.UpdateOrderStatus(ctx, req.Id, model.OrderStatus(req.Status))
+	updatedOrder, err := s.svc.UpdateOrderStatus(ctx, req.Id, model.OrderStatus(req.Status), req.Actor, req.Reason, req.ExpectedVersion)
 	if err != nil {
 		return nil, err
 	}
 
 	// 转换响应
 	return &pb.UpdateOrderResponse{
-		Order: &pb.Order{
-			Id:         updatedOrder.ID.String(),
-			CustomerId: updatedOrder.UserID.String(),
-			Items:      convertToProtoItems(updatedOrder.Items),
-			TotalPrice: updatedOrder.TotalPrice,
-			Status:     string(updatedOrder.Status),
-			CreatedAt:  updatedOrder.CreatedAt,
-			UpdatedAt:  updatedOrder.UpdatedAt,
-		},
+		Order: convertToProtoOrder(updatedOrder),
+	}, nil
+}
+
+func (s *OrderController) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
+	cancelledOrder, err := s.svc.CancelOrder(ctx, req.Id, req.Actor, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CancelOrderResponse{
+		Order: convertToProtoOrder(cancelledOrder),
 	}, nil
 }
 
+func (s *OrderController) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	filter := model.OrderFilter{
+		UserID:        req.CustomerId,
+		Status:        model.OrderStatus(req.Status),
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	}
+	orders, nextPageToken, err := s.svc.ListOrders(ctx, filter, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	protoOrders := make([]*pb.Order, 0, len(orders))
+	for _, order := range orders {
+		protoOrders = append(protoOrders, convertToProtoOrder(order))
+	}
+	return &pb.ListOrdersResponse{
+		Orders:        protoOrders,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// 辅助函数：转换订单领域模型到proto消息
+func convertToProtoOrder(order *model.Order) *pb.Order {
+	return &pb.Order{
+		Id:         order.ID.String(),
+		CustomerId: order.UserID.String(),
+		Items:      convertToProtoItems(order.Items),
+		TotalPrice: order.TotalPrice,
+		Status:     string(order.Status),
+		CreatedAt:  order.CreatedAt,
+		UpdatedAt:  order.UpdatedAt,
+		ExpiresAt:  order.ExpiresAt,
+		Version:    order.Version,
+
+		StatusHistory: convertToProtoHistory(order.StatusHistory),
+	}
+}
+
+func convertToProtoHistory(history []model.StatusChange) []*pb.StatusChange {
+	protoHistory := make([]*pb.StatusChange, 0, len(history))
+	for _, change := range history {
+		protoHistory = append(protoHistory, convertToProtoChange(change))
+	}
+	return protoHistory
+}
+
+func convertToProtoChange(change model.StatusChange) *pb.StatusChange {
+	return &pb.StatusChange{
+		From:      string(change.From),
+		To:        string(change.To),
+		Actor:     change.Actor,
+		Reason:    change.Reason,
+		ChangedAt: change.ChangedAt,
+	}
+}
+
 // 辅助函数：转换领域模型到proto消息
 func convertToProtoItems(items []model.OrderItem) []*pb.OrderItem {
 	protoItems := make([]*pb.OrderItem, 0, len(items))
 	for _, item := range items {
 		protoItems = append(protoItems, &pb.OrderItem{
-			ProductId: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
+			ProductId:   item.ProductID,
+			Sku:         item.SKU,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+			ProductName: item.ProductName,
 		})
 	}
 	return protoItems
@@ -114,3 +173,13 @@ func convertToUUID(key string) uuid.UUID {
 	}
 	return uid
 }
+
+func (s *OrderController) WatchOrder(req *pb.GetOrderRequest, stream grpc.ServerStreamingServer[pb.OrderEvent]) error {
+	return s.svc.WatchOrder(stream.Context(), req.Id, func(event *model.OrderEvent) error {
+		return stream.Send(&pb.OrderEvent{
+			OrderId: event.Order.ID.String(),
+			Change:  convertToProtoChange(event.Change),
+			Order:   convertToProtoOrder(event.Order),
+		})
+	})
+}
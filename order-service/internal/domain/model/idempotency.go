@@ -0,0 +1,8 @@
+package model
+
+// IdempotencyRecord 记录某个幂等键对应的请求摘要与创建结果，
+// Order 为空表示首个请求仍在处理中
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Order       *Order `json:"order,omitempty"`
+}
@@ -8,13 +8,99 @@ const (
 	OrderStatusPending    OrderStatus = "pending"
 	OrderStatusProcessing OrderStatus = "processing"
 	OrderStatusCompleted  OrderStatus = "completed"
+	OrderStatusCancelling OrderStatus = "cancelling"
 	OrderStatusCancelled  OrderStatus = "cancelled"
+	// OrderStatusFailed 订单因库存不足等原因无法履约
+	OrderStatusFailed OrderStatus = "failed"
+	// OrderStatusPaymentFailed 支付渠道扣款失败，订单锁定的库存已由库存服务归还
+	OrderStatusPaymentFailed OrderStatus = "payment_failed"
 )
 
+// transitions 定义订单状态机允许的状态迁移，未列出的状态为终态
+var transitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:    {OrderStatusProcessing, OrderStatusCompleted, OrderStatusCancelling, OrderStatusFailed, OrderStatusPaymentFailed},
+	OrderStatusProcessing: {OrderStatusCompleted, OrderStatusCancelling, OrderStatusFailed, OrderStatusPaymentFailed},
+	OrderStatusCompleted:  {OrderStatusCancelling},
+	OrderStatusCancelling: {OrderStatusCancelled},
+}
+
+// orderStatuses 全部已定义的订单状态
+var orderStatuses = []OrderStatus{
+	OrderStatusPending, OrderStatusProcessing, OrderStatusCompleted, OrderStatusCancelling, OrderStatusCancelled, OrderStatusFailed, OrderStatusPaymentFailed,
+}
+
+// AwaitingPayment 判断订单是否仍在等待支付，只有这些状态的订单会因超时被取消
+func (s OrderStatus) AwaitingPayment() bool {
+	return s == OrderStatusPending || s == OrderStatusProcessing
+}
+
+// AwaitingPaymentStatuses 返回全部等待支付的订单状态，供超时扫描查询使用
+func AwaitingPaymentStatuses() []OrderStatus {
+	var statuses []OrderStatus
+	for _, s := range orderStatuses {
+		if s.AwaitingPayment() {
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses
+}
+
+// Valid 判断状态是否为已定义的订单状态
+func (s OrderStatus) Valid() bool {
+	for _, status := range orderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Terminal 判断状态是否为终态，终态订单不会再发生任何状态变更
+func (s OrderStatus) Terminal() bool {
+	return len(transitions[s]) == 0
+}
+
+// CanTransitionTo 判断状态机是否允许从当前状态迁移到 to
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange 记录一次订单状态变更
+type StatusChange struct {
+	From      OrderStatus `json:"from" bson:"from"`
+	To        OrderStatus `json:"to" bson:"to"`
+	Actor     string      `json:"actor" bson:"actor"`
+	Reason    string      `json:"reason" bson:"reason"`
+	ChangedAt string      `json:"changed_at" bson:"changed_at"`
+}
+
+// OrderEvent 是推送给订阅者的订单状态变更
+type OrderEvent struct {
+	Change StatusChange `json:"change"`
+	Order  *Order       `json:"order"`
+}
+
 type OrderItem struct {
 	ProductID int64 `json:"product_id" bson:"product_id"`
-	Quantity  int64 `json:"quantity" bson:"quantity"`
-	Price     int64 `json:"price" bson:"price"`
+	// SKU 商品规格的库存单位编码，引入多规格商品前创建的订单为空
+	SKU      string `json:"sku" bson:"sku,omitempty"`
+	Quantity int64  `json:"quantity" bson:"quantity"`
+	// 下单时的商品单价与名称快照，取自库存服务的产品目录
+	Price       int64  `json:"price" bson:"price"`
+	ProductName string `json:"product_name" bson:"product_name"`
+}
+
+// Product 是库存服务产品目录中的商品
+type Product struct {
+	ProductID   int64
+	SKU         string
+	ProductName string
+	Price       int64
 }
 
 type Order struct {
@@ -25,4 +111,38 @@ type Order struct {
 	Status     OrderStatus `json:"status" bson:"status"`
 	CreatedAt  string      `json:"created_at" bson:"created_at"`
 	UpdatedAt  string      `json:"updated_at" bson:"updated_at"`
+	// 支付截止时间，超时仍处于 pending 或 processing 的订单会被自动取消
+	ExpiresAt string `json:"expires_at" bson:"expires_at"`
+	// 版本号，每次修改订单递增，用于乐观并发控制
+	Version int64 `json:"version" bson:"version"`
+	// 取消订单时库存、支付两项补偿是否已由下游服务确认
+	StockReleased   bool `json:"stock_released" bson:"stock_released"`
+	PaymentRefunded bool `json:"payment_refunded" bson:"payment_refunded"`
+	// 按时间顺序记录的状态变更历史
+	StatusHistory []StatusChange `json:"status_history" bson:"status_history"`
+	// 对账任务为订单重新发布事件的次数及下次对账的最早时间，订单状态变更后清空
+	ReconcileAttempts int64  `json:"-" bson:"reconcile_attempts"`
+	NextReconcileAt   string `json:"-" bson:"next_reconcile_at"`
+}
+
+// Compensation 标识取消订单时需要下游服务确认的补偿项，取值与存储字段名一致
+type Compensation string
+
+const (
+	CompensationStock   Compensation = "stock_released"
+	CompensationPayment Compensation = "payment_refunded"
+)
+
+// OrderFilter 描述订单列表查询的过滤条件，空值表示不过滤
+type OrderFilter struct {
+	UserID        string
+	Status        OrderStatus
+	CreatedAfter  string
+	CreatedBefore string
+}
+
+// OrderCursor 记录分页位置，按 created_at、_id 倒序翻页
+type OrderCursor struct {
+	CreatedAt string `json:"c"`
+	ID        string `json:"i"`
 }
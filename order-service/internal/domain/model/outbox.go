@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// OutboxEvent 是写入发件箱的领域事件，与业务数据在同一事务中落库，
+// 由 relay 异步投递到 RabbitMQ 后标记为已发送
+type OutboxEvent struct {
+	ID         string     `bson:"_id"`
+	RoutingKey string     `bson:"routing_key"`
+	Payload    string     `bson:"payload"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	SentAt     *time.Time `bson:"sent_at"`
+
+	// ClaimedBy 与 ClaimedUntil 为领取该事件投递的 relay 副本及领取期限，期限内其他副本不会投递该事件
+	ClaimedBy    string     `bson:"claimed_by"`
+	ClaimedUntil *time.Time `bson:"claimed_until"`
+}
@@ -2,76 +2,339 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"log"
 	"order-microsystem/order-service/internal/domain/model"
+	"sync/atomic"
 	"time"
 )
 
 type OrderRepository struct {
+	client     *mongo.Client
 	collection *mongo.Collection
+	outbox     *OutboxRepository
+	// standalone 为 true 表示 MongoDB 不支持事务，写入不再开启事务
+	standalone atomic.Bool
 }
 
-func NewOrderRepository(db *mongo.Database) *OrderRepository {
+// orderDocument 是订单在 MongoDB 中的存储结构，ID 以字符串形式保存
+type orderDocument struct {
+	ID         string            `bson:"_id"`
+	UserID     string            `bson:"user_id"`
+	Items      []model.OrderItem `bson:"items"`
+	TotalPrice int64             `bson:"total_price"`
+	Status     model.OrderStatus `bson:"status"`
+	CreatedAt  string            `bson:"created_at"`
+	UpdatedAt  string            `bson:"updated_at"`
+	ExpiresAt  string            `bson:"expires_at"`
+	Version    int64             `bson:"version"`
+
+	StockReleased   bool                 `bson:"stock_released"`
+	PaymentRefunded bool                 `bson:"payment_refunded"`
+	StatusHistory   []model.StatusChange `bson:"status_history"`
+
+	ReconcileAttempts int64  `bson:"reconcile_attempts"`
+	NextReconcileAt   string `bson:"next_reconcile_at"`
+}
+
+func (d *orderDocument) toModel() *model.Order {
+	return &model.Order{
+		ID:         convertUUID(d.ID),
+		UserID:     convertUUID(d.UserID),
+		Items:      d.Items,
+		TotalPrice: d.TotalPrice,
+		Status:     d.Status,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+		ExpiresAt:  d.ExpiresAt,
+		Version:    d.Version,
+
+		StockReleased:   d.StockReleased,
+		PaymentRefunded: d.PaymentRefunded,
+		StatusHistory:   d.StatusHistory,
+
+		ReconcileAttempts: d.ReconcileAttempts,
+		NextReconcileAt:   d.NextReconcileAt,
+	}
+}
+
+func NewOrderRepository(db *mongo.Database, outbox *OutboxRepository) *OrderRepository {
 	return &OrderRepository{
+		client:     db.Client(),
 		collection: db.Collection("orders"),
+		outbox:     outbox,
 	}
 }
 
-func (r *OrderRepository) Create(ctx context.Context, order *model.Order) error {
-	_, err := r.collection.InsertOne(ctx, bson.M{
-		"_id":         order.ID.String(),
-		"user_id":     order.UserID.String(),
-		"items":       order.Items,
-		"total_price": order.TotalPrice,
-		"status":      order.Status,
-		"created_at":  order.CreatedAt,
-		"updated_at":  order.UpdatedAt,
+// EnsureIndexes 创建订单列表查询所需的索引
+func (r *OrderRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		// 超时订单扫描
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+		// 卡住订单对账
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}}},
 	})
 	return err
 }
 
+// Create 在同一事务中写入订单与待发送的领域事件
+func (r *OrderRepository) Create(ctx context.Context, order *model.Order, events ...*model.OutboxEvent) error {
+	return r.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		_, err := r.collection.InsertOne(sessCtx, bson.M{
+			"_id":         order.ID.String(),
+			"user_id":     order.UserID.String(),
+			"items":       order.Items,
+			"total_price": order.TotalPrice,
+			"status":      order.Status,
+			"created_at":  order.CreatedAt,
+			"updated_at":  order.UpdatedAt,
+			"expires_at":  order.ExpiresAt,
+			"version":     order.Version,
+
+			"status_history": order.StatusHistory,
+		})
+		if err != nil {
+			return err
+		}
+		return r.outbox.insert(sessCtx, events)
+	})
+}
+
 func (r *OrderRepository) GetByID(ctx context.Context, id string) (*model.Order, error) {
-	var result struct {
-		ID         string            `bson:"_id"`
-		UserID     string            `bson:"user_id"`
-		Items      []model.OrderItem `bson:"items"`
-		TotalPrice int64             `bson:"total_price"`
-		Status     model.OrderStatus `bson:"status"`
-		CreatedAt  string            `bson:"created_at"`
-		UpdatedAt  string            `bson:"updated_at"`
+	var result orderDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	if err != nil {
+		return nil, err
 	}
 
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	return result.toModel(), nil
+}
+
+// List 按创建时间倒序返回满足过滤条件的订单，cursor 不为空时从该位置之后开始
+func (r *OrderRepository) List(ctx context.Context, filter model.OrderFilter, cursor *model.OrderCursor, limit int64) ([]*model.Order, error) {
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	createdAt := bson.M{}
+	if filter.CreatedAfter != "" {
+		createdAt["$gte"] = filter.CreatedAfter
+	}
+	if filter.CreatedBefore != "" {
+		createdAt["$lt"] = filter.CreatedBefore
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+	if cursor != nil {
+		query["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"created_at": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+	cur, err := r.collection.Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
+	defer cur.Close(ctx)
 
-	return &model.Order{
-		ID:         convertUUID(result.ID),
-		UserID:     convertUUID(result.UserID),
-		Items:      result.Items,
-		TotalPrice: result.TotalPrice,
-		Status:     result.Status,
-		CreatedAt:  result.CreatedAt,
-		UpdatedAt:  result.UpdatedAt,
-	}, nil
-}
-
-func (r *OrderRepository) UpdateStatus(ctx context.Context, id string, status model.OrderStatus) error {
-	_, err := r.collection.UpdateOne(
+	var docs []orderDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	orders := make([]*model.Order, 0, len(docs))
+	for i := range docs {
+		orders = append(orders, docs[i].toModel())
+	}
+	return orders, nil
+}
+
+// ListExpired 按支付截止时间先后返回截止时间早于 now 且仍在等待支付的订单
+func (r *OrderRepository) ListExpired(ctx context.Context, now string, limit int64) ([]*model.Order, error) {
+	query := bson.M{
+		"status":     bson.M{"$in": model.AwaitingPaymentStatuses()},
+		"expires_at": bson.M{"$gt": "", "$lt": now},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "expires_at", Value: 1}}).
+		SetLimit(limit)
+	cur, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var docs []orderDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	orders := make([]*model.Order, 0, len(docs))
+	for i := range docs {
+		orders = append(orders, docs[i].toModel())
+	}
+	return orders, nil
+}
+
+// ListStale 按更新时间先后返回处于 statuses 之一、最后更新时间早于 before 且已到下次对账时间(早于 now)的订单
+func (r *OrderRepository) ListStale(ctx context.Context, statuses []model.OrderStatus, before string, now string, limit int64) ([]*model.Order, error) {
+	query := bson.M{
+		"status":     bson.M{"$in": statuses},
+		"updated_at": bson.M{"$lt": before},
+		"$or": bson.A{
+			bson.M{"next_reconcile_at": nil},
+			bson.M{"next_reconcile_at": bson.M{"$lt": now}},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}}).
+		SetLimit(limit)
+	cur, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var docs []orderDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	orders := make([]*model.Order, 0, len(docs))
+	for i := range docs {
+		orders = append(orders, docs[i].toModel())
+	}
+	return orders, nil
+}
+
+// Transition 仅当订单版本号仍为 version 时将状态更新为 change.To、递增版本号，
+// 并把本次变更追加到状态历史中，返回是否更新成功；更新成功时 events 在同一事务中写入发件箱
+func (r *OrderRepository) Transition(ctx context.Context, id string, version int64, change model.StatusChange, events ...*model.OutboxEvent) (bool, error) {
+	var updated bool
+	err := r.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := r.collection.UpdateOne(
+			sessCtx,
+			bson.M{"_id": id, "version": versionFilter(version)},
+			bson.M{
+				"$set": bson.M{
+					"status":     change.To,
+					"updated_at": change.ChangedAt,
+				},
+				"$inc": bson.M{"version": 1},
+				"$push": bson.M{
+					"status_history": change,
+				},
+				// 状态变更后重新开始计算对账退避
+				"$unset": bson.M{"reconcile_attempts": "", "next_reconcile_at": ""},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		updated = result.ModifiedCount == 1
+		if !updated {
+			return nil
+		}
+		return r.outbox.insert(sessCtx, events)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// ScheduleReconcile 在同一事务中写入对账任务重新发布的事件、递增订单的对账次数并记录下次对账的最早时间 next
+func (r *OrderRepository) ScheduleReconcile(ctx context.Context, id string, next string, events ...*model.OutboxEvent) error {
+	return r.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		_, err := r.collection.UpdateOne(sessCtx,
+			bson.M{"_id": id},
+			bson.M{
+				"$set": bson.M{"next_reconcile_at": next},
+				"$inc": bson.M{"reconcile_attempts": 1},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		return r.outbox.insert(sessCtx, events)
+	})
+}
+
+// AddEvents 写入与状态变更无关的领域事件，例如需要重新投递的补偿事件
+func (r *OrderRepository) AddEvents(ctx context.Context, events ...*model.OutboxEvent) error {
+	return r.outbox.insert(ctx, events)
+}
+
+// ConfirmCompensation 记录一项补偿已完成，并返回更新后的订单
+func (r *OrderRepository) ConfirmCompensation(ctx context.Context, id string, compensation model.Compensation) (*model.Order, error) {
+	var result orderDocument
+	err := r.collection.FindOneAndUpdate(
 		ctx,
 		bson.M{"_id": id},
 		bson.M{
 			"$set": bson.M{
-				"status":     status,
-				"updated_at": time.Now().Format(time.RFC3339),
+				string(compensation): true,
+				"updated_at":         time.Now().Format(time.RFC3339),
 			},
+			"$inc": bson.M{"version": 1},
 		},
-	)
-	return err
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return result.toModel(), nil
+}
+
+// versionFilter 匹配指定版本号的订单；引入版本号之前创建的订单没有 version 字段，视为版本 0
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// errCodeIllegalOperation 是单机部署的 MongoDB 收到事务请求时返回的错误码
+const errCodeIllegalOperation = 20
+
+// withTransaction 在 MongoDB 事务中执行 fn。事务要求 MongoDB 部署为副本集，单机部署返回 IllegalOperation 时
+// 记录一次告警并退化为不开启事务执行，此时订单变更与发件箱事件不再保证原子写入
+func (r *OrderRepository) withTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	if !r.standalone.Load() {
+		_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sessCtx)
+		})
+		if !isTransactionUnsupported(err) {
+			return err
+		}
+		r.standalone.Store(true)
+		log.Printf("MongoDB does not support transactions, deploy it as a replica set; falling back to non-transactional writes: %v", err)
+	}
+	return mongo.WithSession(ctx, session, fn)
+}
+
+// isTransactionUnsupported 判断错误是否因 MongoDB 不是副本集而不支持事务
+func isTransactionUnsupported(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == errCodeIllegalOperation
 }
 
 func convertUUID(source string) uuid.UUID {
@@ -0,0 +1,109 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"order-microsystem/order-service/internal/domain/model"
+	"order-microsystem/outbox"
+	"time"
+)
+
+type OutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{
+		collection: db.Collection("outbox"),
+	}
+}
+
+// EnsureIndexes 创建 relay 扫描待发送事件所需的索引
+func (r *OutboxRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sent_at", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	return err
+}
+
+// ClaimPending 按写入顺序领取尚未发送的事件：先返回 owner 此前领取且仍在租约内的事件，
+// 再逐条以原子的 findOneAndUpdate 领取未被领取或租约已过期的事件，领取后的 lease 内其他副本不会再领取
+func (r *OutboxRepository) ClaimPending(ctx context.Context, owner string, lease time.Duration, limit int) ([]*outbox.Event, error) {
+	now := time.Now()
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(int64(limit))
+	cur, err := r.collection.Find(ctx, bson.M{
+		"sent_at":       nil,
+		"claimed_by":    owner,
+		"claimed_until": bson.M{"$gte": now},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var events []*model.OutboxEvent
+	if err := cur.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	claimOpts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+	for len(events) < limit {
+		var event model.OutboxEvent
+		err := r.collection.FindOneAndUpdate(ctx,
+			bson.M{
+				"sent_at": nil,
+				"$or": bson.A{
+					bson.M{"claimed_until": nil},
+					bson.M{"claimed_until": bson.M{"$lt": now}},
+				},
+			},
+			bson.M{"$set": bson.M{"claimed_by": owner, "claimed_until": now.Add(lease)}},
+			claimOpts,
+		).Decode(&event)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	claimed := make([]*outbox.Event, 0, len(events))
+	for _, event := range events {
+		claimed = append(claimed, &outbox.Event{
+			ID:         event.ID,
+			RoutingKey: event.RoutingKey,
+			Payload:    event.Payload,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return claimed, nil
+}
+
+// MarkSent 将事件标记为已发送
+func (r *OutboxRepository) MarkSent(ctx context.Context, id string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"sent_at": time.Now()}},
+	)
+	return err
+}
+
+// insert 写入事件，ctx 为事务会话上下文时与业务数据一同提交
+func (r *OutboxRepository) insert(ctx context.Context, events []*model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		docs = append(docs, event)
+	}
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"fmt"
+	"github.com/hashicorp/consul/api"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"math/rand"
+	"order-microsystem/order-service/pkg/config"
+	"sync"
+)
+
+// serviceConn 按需通过 Consul 发现下游服务并建立 gRPC 连接，
+// 订单服务启动时下游服务可能尚未注册，因此不在启动阶段解析地址
+type serviceConn struct {
+	cfg  *config.ConsulConfig
+	name string
+
+	mu   sync.Mutex
+	conn *grpc.ClientConn
+}
+
+func newServiceConn(cfg *config.ConsulConfig, name string) *serviceConn {
+	return &serviceConn{cfg: cfg, name: name}
+}
+
+// get 返回已建立的连接，尚未建立或连接已失败时从 Consul 重新查询健康实例后建立
+func (c *serviceConn) get() (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		switch c.conn.GetState() {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			c.conn.Close()
+			c.conn = nil
+		default:
+			return c.conn, nil
+		}
+	}
+
+	consulConfig := api.DefaultConfig()
+	consulConfig.Address = fmt.Sprintf("%s:%d", c.cfg.Host, c.cfg.Port)
+	client, err := api.NewClient(consulConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul client: %v", err)
+	}
+
+	services, _, err := client.Health().Service(c.name, "", true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service %s: %v", c.name, err)
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no healthy instances of %s available", c.name)
+	}
+
+	service := services[rand.Intn(len(services))].Service
+	conn, err := grpc.NewClient(
+		fmt.Sprintf("%s:%d", service.Address, service.Port),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
+			otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
+		)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", c.name, err)
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+// release 在调用因下游不可用失败时丢弃 conn，下次 get 重新从 Consul 选择实例
+func (c *serviceConn) release(conn *grpc.ClientConn, err error) {
+	if status.Code(err) != codes.Unavailable {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == conn {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+func (c *serviceConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,59 @@
+package proxy
+
+import (
+	"context"
+	"order-microsystem/order-service/internal/domain/model"
+	"order-microsystem/order-service/pkg/config"
+	pb "order-microsystem/order-service/pkg/proto/inventory"
+)
+
+// InventoryProxy 调用库存服务
+type InventoryProxy struct {
+	conn *serviceConn
+}
+
+func NewInventoryProxy(cfg *config.Config) *InventoryProxy {
+	return &InventoryProxy{conn: newServiceConn(&cfg.Consul, cfg.Dependencies.Inventory)}
+}
+
+// GetOrderReservations 查询订单的库存预留记录
+func (p *InventoryProxy) GetOrderReservations(ctx context.Context, orderID string) ([]*pb.Reservation, error) {
+	conn, err := p.conn.get()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pb.NewInventoryServiceClient(conn).GetOrderReservations(ctx, &pb.GetOrderReservationsRequest{OrderId: orderID})
+	if err != nil {
+		p.conn.release(conn, err)
+		return nil, err
+	}
+	return resp.Reservations, nil
+}
+
+// BatchGetProducts 按产品ID或 SKU 批量查询产品目录，不存在的产品不会出现在结果中
+func (p *InventoryProxy) BatchGetProducts(ctx context.Context, productIDs []int64, skus []string) ([]*model.Product, error) {
+	conn, err := p.conn.get()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pb.NewInventoryServiceClient(conn).BatchGetProducts(ctx, &pb.BatchGetProductsRequest{ProductIds: productIDs, Skus: skus})
+	if err != nil {
+		p.conn.release(conn, err)
+		return nil, err
+	}
+
+	products := make([]*model.Product, 0, len(resp.Products))
+	for _, product := range resp.Products {
+		products = append(products, &model.Product{
+			ProductID:   product.ProductId,
+			SKU:         product.Sku,
+			ProductName: product.ProductName,
+			Price:       product.Price,
+		})
+	}
+	return products, nil
+}
+
+func (p *InventoryProxy) Close() error {
+	return p.conn.Close()
+}
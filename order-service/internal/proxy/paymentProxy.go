@@ -0,0 +1,39 @@
+package proxy
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"order-microsystem/order-service/pkg/config"
+	pb "order-microsystem/order-service/pkg/proto/payment"
+)
+
+// PaymentProxy 调用支付服务
+type PaymentProxy struct {
+	conn *serviceConn
+}
+
+func NewPaymentProxy(cfg *config.Config) *PaymentProxy {
+	return &PaymentProxy{conn: newServiceConn(&cfg.Consul, cfg.Dependencies.Payment)}
+}
+
+// GetOrderPayment 查询订单的支付记录，订单尚未支付时返回 nil
+func (p *PaymentProxy) GetOrderPayment(ctx context.Context, orderID string) (*pb.Payment, error) {
+	conn, err := p.conn.get()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pb.NewPaymentServiceClient(conn).GetPaymentByOrder(ctx, &pb.GetPaymentByOrderRequest{OrderId: orderID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		p.conn.release(conn, err)
+		return nil, err
+	}
+	return resp.Payment, nil
+}
+
+func (p *PaymentProxy) Close() error {
+	return p.conn.Close()
+}
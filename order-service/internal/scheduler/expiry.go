@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"log"
+	"order-microsystem/order-service/pkg/cache"
+	"time"
+)
+
+const (
+	// expiryLeaseKey 超时订单扫描租约，保证多副本部署时同一时刻只有一个副本在扫描
+	expiryLeaseKey = "order_expiry_sweeper_lease"
+	// expiryBatchSize 每轮最多取消的订单数量
+	expiryBatchSize = 100
+	// defaultExpiryInterval 未配置扫描间隔时使用的默认值
+	defaultExpiryInterval = 30 * time.Second
+)
+
+// OrderExpirer 取消超过支付截止时间的订单
+type OrderExpirer interface {
+	ExpireOrders(ctx context.Context, limit int64) (int, error)
+}
+
+// ExpirySweeper 周期性扫描并取消超时未支付的订单
+type ExpirySweeper struct {
+	expirer     OrderExpirer
+	redisClient *cache.RedisClient
+	interval    time.Duration
+	owner       string
+}
+
+func NewExpirySweeper(expirer OrderExpirer, redis *cache.RedisClient, interval time.Duration) *ExpirySweeper {
+	if interval <= 0 {
+		interval = defaultExpiryInterval
+	}
+	return &ExpirySweeper{
+		expirer:     expirer,
+		redisClient: redis,
+		interval:    interval,
+		owner:       uuid.New().String(),
+	}
+}
+
+// Run 持续扫描超时订单，直到 ctx 被取消
+func (s *ExpirySweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.redisClient.ReleaseLease(expiryLeaseKey, s.owner); err != nil {
+				log.Printf("expiry sweeper: failed to release lease: %v", err)
+			}
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *ExpirySweeper) sweep(ctx context.Context) {
+	// 租约时长为两个扫描周期，持有者每轮续期，宕机后其他副本最多等待两个周期即可接管
+	held, err := s.redisClient.AcquireLease(expiryLeaseKey, s.owner, 2*s.interval)
+	if err != nil {
+		log.Printf("expiry sweeper: failed to acquire lease: %v", err)
+		return
+	}
+	if !held {
+		return
+	}
+
+	// 一轮取消满批次时继续处理，直到没有积压的超时订单
+	for {
+		expired, err := s.expirer.ExpireOrders(ctx, expiryBatchSize)
+		if err != nil {
+			log.Printf("expiry sweeper: %v", err)
+			return
+		}
+		if expired > 0 {
+			log.Printf("expiry sweeper: cancelled %d expired orders", expired)
+		}
+		if expired < expiryBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"order-microsystem/order-service/internal/domain/model"
+	"order-microsystem/order-service/pkg/cache"
+	"order-microsystem/order-service/pkg/monitoring"
+	inventorypb "order-microsystem/order-service/pkg/proto/inventory"
+	paymentpb "order-microsystem/order-service/pkg/proto/payment"
+	"time"
+)
+
+const (
+	// reconcileLeaseKey 对账任务租约，保证多副本部署时同一时刻只有一个副本在对账
+	reconcileLeaseKey = "order_reconciler_lease"
+	// reconcileBatchSize 每轮最多核对的订单数量
+	reconcileBatchSize = 50
+	// reconcileCallTimeout 每次查询下游服务的超时时间
+	reconcileCallTimeout = 5 * time.Second
+
+	// reservationLocked、reservationCommitted 为库存服务中仍占用库存的预留状态
+	reservationLocked    = "locked"
+	reservationCommitted = "committed"
+	// 支付服务中的支付状态：failed 为扣款失败，没有需要退款的金额；refunded 为已全额退款；
+	// captured、partially_refunded 为已扣款，pending、authorized 为仍在扣款中
+	paymentFailed            = "failed"
+	paymentCaptured          = "captured"
+	paymentPartiallyRefunded = "partially_refunded"
+	paymentRefunded          = "refunded"
+
+	defaultReconcileInterval = time.Minute
+	defaultReconcileAfter    = 10 * time.Minute
+)
+
+// 对账动作，作为 order_reconciler_actions_total 的 action 标签
+const (
+	actionCompleted         = "completed"
+	actionPaymentFailed     = "payment_failed"
+	actionRepublishCreated  = "republish_order_created"
+	actionStockConfirmed    = "confirm_stock_released"
+	actionPaymentConfirmed  = "confirm_payment_refunded"
+	actionRepublishCanceled = "republish_order_cancelled"
+	actionFailed            = "failed"
+)
+
+// OrderReconciler 是对账任务推进订单或补发事件所需的订单服务接口
+type OrderReconciler interface {
+	ListStuckOrders(ctx context.Context, stuckAfter time.Duration, limit int64) ([]*model.Order, error)
+	CompletePayment(ctx context.Context, orderID string) error
+	FailPayment(ctx context.Context, orderID string, reasonCode string) error
+	ConfirmCompensation(ctx context.Context, orderID string, compensation model.Compensation) error
+	RepublishOrderCreated(ctx context.Context, order *model.Order) error
+	RepublishOrderCancelled(ctx context.Context, order *model.Order, reason string) error
+}
+
+// InventoryClient 查询库存服务中订单的库存预留
+type InventoryClient interface {
+	GetOrderReservations(ctx context.Context, orderID string) ([]*inventorypb.Reservation, error)
+}
+
+// PaymentClient 查询支付服务中订单的支付记录
+type PaymentClient interface {
+	GetOrderPayment(ctx context.Context, orderID string) (*paymentpb.Payment, error)
+}
+
+// Reconciler 定时核对停留在非终态过久的订单：向库存服务、支付服务查询真实状态后推进订单，
+// 或重新发布可能丢失的事件，即 README 中的“定时任务补偿”
+type Reconciler struct {
+	orders      OrderReconciler
+	inventory   InventoryClient
+	payment     PaymentClient
+	redisClient *cache.RedisClient
+	interval    time.Duration
+	stuckAfter  time.Duration
+	owner       string
+}
+
+func NewReconciler(orders OrderReconciler, inventory InventoryClient, payment PaymentClient, redis *cache.RedisClient, interval time.Duration, stuckAfter time.Duration) *Reconciler {
+	if interval <= 0 {
+		interval = defaultReconcileInterval
+	}
+	if stuckAfter <= 0 {
+		stuckAfter = defaultReconcileAfter
+	}
+	return &Reconciler{
+		orders:      orders,
+		inventory:   inventory,
+		payment:     payment,
+		redisClient: redis,
+		interval:    interval,
+		stuckAfter:  stuckAfter,
+		owner:       uuid.New().String(),
+	}
+}
+
+// Run 持续执行对账，直到 ctx 被取消
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.redisClient.ReleaseLease(reconcileLeaseKey, r.owner); err != nil {
+				log.Printf("reconciler: failed to release lease: %v", err)
+			}
+			return
+		case <-ticker.C:
+			r.reconcileAll(ctx)
+		}
+	}
+}
+
+func (r *Reconciler) reconcileAll(ctx context.Context) {
+	held, err := r.redisClient.AcquireLease(reconcileLeaseKey, r.owner, 2*r.interval)
+	if err != nil {
+		log.Printf("reconciler: failed to acquire lease: %v", err)
+		return
+	}
+	if !held {
+		return
+	}
+
+	orders, err := r.orders.ListStuckOrders(ctx, r.stuckAfter, reconcileBatchSize)
+	if err != nil {
+		log.Printf("reconciler: %v", err)
+		return
+	}
+	for _, order := range orders {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := r.reconcile(ctx, order); err != nil {
+			monitoring.ReconcilerActions.WithLabelValues(actionFailed).Inc()
+			log.Printf("reconciler: failed to reconcile order %s: %v", order.ID, err)
+		}
+	}
+}
+
+// reconcile 核对单个订单并采取相应动作
+func (r *Reconciler) reconcile(ctx context.Context, order *model.Order) error {
+	switch order.Status {
+	case model.OrderStatusPending, model.OrderStatusProcessing:
+		return r.reconcileAwaitingPayment(ctx, order)
+	case model.OrderStatusCancelling:
+		return r.reconcileCancelling(ctx, order)
+	}
+	return nil
+}
+
+// reconcileAwaitingPayment 处理等待支付的订单：支付已扣款则直接完成订单，扣款失败则将订单置为支付失败，
+// 尚未支付或仍在扣款中则重新发布 order.created，由库存服务与支付服务补齐丢失的环节
+func (r *Reconciler) reconcileAwaitingPayment(ctx context.Context, order *model.Order) error {
+	payment, err := r.lookupPayment(ctx, order)
+	if err != nil {
+		return err
+	}
+	switch payment.GetStatus() {
+	case paymentFailed:
+		if err := r.orders.FailPayment(ctx, order.ID.String(), payment.FailureCode); err != nil {
+			return err
+		}
+		r.record(order, actionPaymentFailed)
+		return nil
+	case paymentCaptured, paymentPartiallyRefunded, paymentRefunded:
+		if err := r.orders.CompletePayment(ctx, order.ID.String()); err != nil {
+			return err
+		}
+		r.record(order, actionCompleted)
+		return nil
+	}
+
+	if err := r.orders.RepublishOrderCreated(ctx, order); err != nil {
+		return err
+	}
+	r.record(order, actionRepublishCreated)
+	return nil
+}
+
+// reconcileCancelling 处理取消中的订单：下游已无需补偿的项直接确认，
+// 仍有库存被占用或存在未失败且未全额退款的支付时重新发布 order.cancelled
+func (r *Reconciler) reconcileCancelling(ctx context.Context, order *model.Order) error {
+	republish := false
+
+	if !order.StockReleased {
+		reservations, err := r.lookupReservations(ctx, order)
+		if err != nil {
+			return err
+		}
+		held := false
+		for _, reservation := range reservations {
+			if reservation.Status == reservationLocked || reservation.Status == reservationCommitted {
+				held = true
+				break
+			}
+		}
+		if held {
+			republish = true
+		} else {
+			if err := r.orders.ConfirmCompensation(ctx, order.ID.String(), model.CompensationStock); err != nil {
+				return err
+			}
+			r.record(order, actionStockConfirmed)
+		}
+	}
+
+	if !order.PaymentRefunded {
+		payment, err := r.lookupPayment(ctx, order)
+		if err != nil {
+			return err
+		}
+		if payment != nil && payment.Status != paymentFailed && payment.Status != paymentRefunded {
+			// 支付仍需退款或仍在扣款中，交由支付服务幂等处理
+			republish = true
+		} else {
+			if err := r.orders.ConfirmCompensation(ctx, order.ID.String(), model.CompensationPayment); err != nil {
+				return err
+			}
+			r.record(order, actionPaymentConfirmed)
+		}
+	}
+
+	if !republish {
+		return nil
+	}
+	if err := r.orders.RepublishOrderCancelled(ctx, order, "reconciler: compensation not confirmed"); err != nil {
+		return err
+	}
+	r.record(order, actionRepublishCanceled)
+	return nil
+}
+
+func (r *Reconciler) lookupPayment(ctx context.Context, order *model.Order) (*paymentpb.Payment, error) {
+	callCtx, cancel := context.WithTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	payment, err := r.payment.GetOrderPayment(callCtx, order.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment: %v", err)
+	}
+	return payment, nil
+}
+
+func (r *Reconciler) lookupReservations(ctx context.Context, order *model.Order) ([]*inventorypb.Reservation, error) {
+	callCtx, cancel := context.WithTimeout(ctx, reconcileCallTimeout)
+	defer cancel()
+	reservations, err := r.inventory.GetOrderReservations(callCtx, order.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservations: %v", err)
+	}
+	return reservations, nil
+}
+
+func (r *Reconciler) record(order *model.Order, action string) {
+	monitoring.ReconcilerActions.WithLabelValues(action).Inc()
+	log.Printf("reconciler: order %s (%s): %s", order.ID, order.Status, action)
+}
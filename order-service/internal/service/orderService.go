@@ -2,65 +2,283 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"math"
 	"order-microsystem/order-service/internal/domain/model"
 	"order-microsystem/order-service/pkg/cache"
 	"order-microsystem/order-service/pkg/messaging"
+	"strconv"
 	"time"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// maxTransitionAttempts 状态迁移遇到并发修改时的最大尝试次数
+	maxTransitionAttempts = 3
+
+	// idempotencyTTL 幂等键的保留时长，超过后相同的键会被视为新请求
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyPendingTTL 占用中的幂等键的保留时长，进程在保存结果前崩溃时相同的键在此之后即可重试，
+	// 保存创建结果时再延长到 idempotencyTTL
+	idempotencyPendingTTL = time.Minute
+	// maxIdempotencyKeyLength 幂等键的最大长度
+	maxIdempotencyKeyLength = 255
+	// idempotencyKeyReusedReason 幂等键被用于不同请求内容时，FailedPrecondition 错误附带的 ErrorInfo 原因，网关据此返回 422
+	idempotencyKeyReusedReason = "IDEMPOTENCY_KEY_REUSED"
+	// maxItemQuantity 单个订单商品的最大购买数量
+	maxItemQuantity = 10000
+
+	// reconcileBackoffBase 对账任务重新发布事件后再次对账该订单的等待时间，随重新发布次数翻倍，最长 maxReconcileBackoff
+	reconcileBackoffBase = time.Minute
+	maxReconcileBackoff  = time.Hour
+)
+
 type OrderRepository interface {
-	Create(ctx context.Context, order *model.Order) error
+	Create(ctx context.Context, order *model.Order, events ...*model.OutboxEvent) error
 	GetByID(ctx context.Context, id string) (*model.Order, error)
-	UpdateStatus(ctx context.Context, id string, status model.OrderStatus) error
+	List(ctx context.Context, filter model.OrderFilter, cursor *model.OrderCursor, limit int64) ([]*model.Order, error)
+	ListExpired(ctx context.Context, now string, limit int64) ([]*model.Order, error)
+	ListStale(ctx context.Context, statuses []model.OrderStatus, before string, now string, limit int64) ([]*model.Order, error)
+	Transition(ctx context.Context, id string, version int64, change model.StatusChange, events ...*model.OutboxEvent) (bool, error)
+	AddEvents(ctx context.Context, events ...*model.OutboxEvent) error
+	ScheduleReconcile(ctx context.Context, id string, next string, events ...*model.OutboxEvent) error
+	ConfirmCompensation(ctx context.Context, id string, compensation model.Compensation) (*model.Order, error)
+}
+
+// ProductCatalog 查询库存服务中的产品目录，下单时以目录价格计算订单金额
+type ProductCatalog interface {
+	BatchGetProducts(ctx context.Context, productIDs []int64, skus []string) ([]*model.Product, error)
 }
 
 type OrderService struct {
 	repo        OrderRepository
-	rabbitmq    *messaging.RabbitMQ
 	redisClient *cache.RedisClient
+	catalog     ProductCatalog
+	// paymentTimeout 下单后等待支付的时长，为 0 表示订单不会超时
+	paymentTimeout time.Duration
 }
 
-func NewOrderService(repo OrderRepository, rabbitmq *messaging.RabbitMQ, redis *cache.RedisClient) *OrderService {
+func NewOrderService(repo OrderRepository, redis *cache.RedisClient, catalog ProductCatalog, paymentTimeout time.Duration) *OrderService {
 	return &OrderService{
-		repo:        repo,
-		rabbitmq:    rabbitmq,
-		redisClient: redis,
+		repo:           repo,
+		redisClient:    redis,
+		catalog:        catalog,
+		paymentTimeout: paymentTimeout,
 	}
 }
 
-func (s *OrderService) CreateOrder(ctx context.Context, customerID uuid.UUID, items []model.OrderItem) (*model.Order, error) {
+// CreateOrder 创建订单。idempotencyKey 不为空时，相同的键与请求内容只会创建一次订单并返回首次的结果，
+// 相同的键搭配不同的请求内容返回 FailedPrecondition
+func (s *OrderService) CreateOrder(ctx context.Context, customerID uuid.UUID, items []model.OrderItem, idempotencyKey string) (*model.Order, error) {
+	if idempotencyKey == "" {
+		return s.createOrder(ctx, customerID, items)
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency key exceeds %d characters", maxIdempotencyKeyLength)
+	}
+
+	requestHash, err := hashCreateRequest(customerID, items)
+	if err != nil {
+		return nil, err
+	}
+	// 幂等键按用户隔离，避免不同用户使用相同的键互相影响
+	key := fmt.Sprintf("idempotency_%s_%s", customerID, idempotencyKey)
+
+	// 先占用幂等键，保证并发的重复请求只有一个会真正创建订单
+	acquired, err := s.redisClient.SetNX(key, &model.IdempotencyRecord{RequestHash: requestHash}, idempotencyPendingTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire idempotency key: %v", err)
+	}
+	if !acquired {
+		var record model.IdempotencyRecord
+		if err := s.redisClient.Get(key, &record); err != nil {
+			return nil, fmt.Errorf("failed to get idempotency record: %v", err)
+		}
+		if record.RequestHash != requestHash {
+			st, err := status.New(codes.FailedPrecondition, "idempotency key was already used with a different request").
+				WithDetails(&errdetails.ErrorInfo{Reason: idempotencyKeyReusedReason, Domain: "order-service"})
+			if err != nil {
+				return nil, fmt.Errorf("failed to build idempotency error: %v", err)
+			}
+			return nil, st.Err()
+		}
+		if record.Order == nil {
+			return nil, status.Error(codes.Aborted, "a request with the same idempotency key is in progress")
+		}
+		return record.Order, nil
+	}
+
+	order, err := s.createOrder(ctx, customerID, items)
+	if err != nil {
+		// createOrder 只在订单写入前或写入失败时返回错误，此时释放幂等键，允许客户端重试
+		if delErr := s.redisClient.Del(key); delErr != nil {
+			log.Printf("failed to release idempotency key %s: %v", key, delErr)
+		}
+		return nil, err
+	}
+
+	// 订单已写入，保存幂等记录失败时不能返回错误，否则客户端重试会在占用标记过期后重复创建订单
+	if err := s.redisClient.SetWithTTL(key, &model.IdempotencyRecord{RequestHash: requestHash, Order: order}, idempotencyTTL); err != nil {
+		log.Printf("failed to save idempotency record %s for order %s: %v", key, order.ID, err)
+	}
+	return order, nil
+}
+
+func (s *OrderService) createOrder(ctx context.Context, customerID uuid.UUID, items []model.OrderItem) (*model.Order, error) {
+	items, err := s.priceItems(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	// 计算总价
 	totalPrice := int64(0)
 	for _, item := range items {
+		if item.Price > 0 && item.Quantity > (math.MaxInt64-totalPrice)/item.Price {
+			return nil, status.Error(codes.InvalidArgument, "order total price overflows")
+		}
 		totalPrice += item.Price * item.Quantity
 	}
 
+	createdAt := time.Now()
+	now := createdAt.Format(time.RFC3339)
+	var expiresAt string
+	if s.paymentTimeout > 0 {
+		expiresAt = createdAt.Add(s.paymentTimeout).Format(time.RFC3339)
+	}
 	order := &model.Order{
 		ID:         uuid.New(),
 		UserID:     customerID,
 		Items:      items,
 		TotalPrice: totalPrice,
-		Status:     "pending",
-		CreatedAt:  time.Now().Format(time.RFC3339),
-		UpdatedAt:  time.Now().Format(time.RFC3339),
+		Status:     model.OrderStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		ExpiresAt:  expiresAt,
+		Version:    1,
+		StatusHistory: []model.StatusChange{{
+			To:        model.OrderStatusPending,
+			Actor:     "customer:" + customerID.String(),
+			Reason:    "order created",
+			ChangedAt: now,
+		}},
 	}
 
-	if err := s.repo.Create(ctx, order); err != nil {
-		return nil, fmt.Errorf("failed to create order: %v", err)
+	event, err := messaging.NewOrderCreatedEvent(order)
+	if err != nil {
+		return nil, err
 	}
-
-	if err := s.rabbitmq.PublishOrderCreated(ctx, order); err != nil {
-		return nil, fmt.Errorf("failed to publish order created event: %v", err)
+	// 订单与 order.created 事件在同一事务中写入，由 relay 负责投递
+	if err := s.repo.Create(ctx, order, event); err != nil {
+		return nil, fmt.Errorf("failed to create order: %v", err)
 	}
 
+	// 订单已提交，缓存写入失败只影响查询命中率，不能让已创建的订单表现为创建失败
 	if err := s.redisClient.Set(fmt.Sprintf("order_%s", order.ID), order); err != nil {
-		return nil, fmt.Errorf("failed to set order in cache: %v", err)
+		log.Printf("failed to set order %s in cache: %v", order.ID, err)
 	}
 	return order, nil
 }
 
+// priceItems 从库存服务查询商品目录，以目录中的单价和名称替换客户端传入的值，并补全商品的产品ID与 SKU。
+// 商品按 SKU 引用时以 SKU 为准；商品不存在、SKU 与产品ID不符或数量不合法时返回 InvalidArgument
+func (s *OrderService) priceItems(ctx context.Context, items []model.OrderItem) ([]model.OrderItem, error) {
+	if len(items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order must contain at least one item")
+	}
+
+	var productIDs []int64
+	var skus []string
+	seenIDs := make(map[int64]bool, len(items))
+	seenSKUs := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 || item.Quantity > maxItemQuantity {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid quantity %d for product %s, must be between 1 and %d", item.Quantity, itemRef(item), maxItemQuantity)
+		}
+		if item.SKU != "" {
+			if !seenSKUs[item.SKU] {
+				seenSKUs[item.SKU] = true
+				skus = append(skus, item.SKU)
+			}
+			continue
+		}
+		if !seenIDs[item.ProductID] {
+			seenIDs[item.ProductID] = true
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+
+	products, err := s.catalog.BatchGetProducts(ctx, productIDs, skus)
+	if err != nil {
+		// 库存服务返回的 gRPC 错误原样返回，例如请求的 SKU 无效时为 InvalidArgument
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unavailable, "failed to look up products: %v", err)
+	}
+	byID := make(map[int64]*model.Product, len(products))
+	bySKU := make(map[string]*model.Product, len(products))
+	for _, product := range products {
+		byID[product.ProductID] = product
+		bySKU[product.SKU] = product
+	}
+
+	priced := make([]model.OrderItem, 0, len(items))
+	for _, item := range items {
+		product, ok := byID[item.ProductID]
+		if item.SKU != "" {
+			product, ok = bySKU[item.SKU]
+		}
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown product: %s", itemRef(item))
+		}
+		if item.ProductID != 0 && item.ProductID != product.ProductID {
+			return nil, status.Errorf(codes.InvalidArgument, "sku %s does not belong to product %d", item.SKU, item.ProductID)
+		}
+		priced = append(priced, model.OrderItem{
+			ProductID:   product.ProductID,
+			SKU:         product.SKU,
+			Quantity:    item.Quantity,
+			Price:       product.Price,
+			ProductName: product.ProductName,
+		})
+	}
+	return priced, nil
+}
+
+// itemRef 返回订单商品在错误信息中的标识，优先使用 SKU
+func itemRef(item model.OrderItem) string {
+	if item.SKU != "" {
+		return "sku " + item.SKU
+	}
+	return strconv.FormatInt(item.ProductID, 10)
+}
+
+// hashCreateRequest 计算创建订单请求内容的摘要，用于判断幂等键是否被用于不同的请求
+func hashCreateRequest(customerID uuid.UUID, items []model.OrderItem) (string, error) {
+	data, err := json.Marshal(struct {
+		CustomerID uuid.UUID         `json:"customer_id"`
+		Items      []model.OrderItem `json:"items"`
+	}{customerID, items})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash request: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 func (s *OrderService) GetOrder(ctx context.Context, id string) (*model.Order, error) {
 	// 先从缓存中获取订单
 	var jsonOorder model.Order
@@ -68,21 +286,444 @@ func (s *OrderService) GetOrder(ctx context.Context, id string) (*model.Order, e
 	if err == nil {
 		return &jsonOorder, nil
 	}
-	return s.repo.GetByID(ctx, id)
+	return s.loadOrder(ctx, id)
+}
+
+// UpdateOrderStatus 按状态机规则手动变更订单状态，actor 与 reason 记录在状态历史中。只允许不需要补偿的 pending -> processing，
+// 取消订单须使用 CancelOrder；expectedVersion 大于 0 时仅当订单版本号与之相同才会更新，否则返回 Aborted
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, id string, to model.OrderStatus, actor string, reason string, expectedVersion int64) (*model.Order, error) {
+	if !to.Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid status: %s", to)
+	}
+	if expectedVersion < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid expected_version: %d", expectedVersion)
+	}
+	// 取消需要发布 order.cancelled 以归还库存并退款，只能通过 CancelOrder 发起
+	if to == model.OrderStatusCancelling || to == model.OrderStatusCancelled {
+		return nil, status.Errorf(codes.FailedPrecondition, "orders cannot be moved to %s directly, use CancelOrder instead", to)
+	}
+	// completed 需要库存服务提交预留，failed 与 payment_failed 需要归还库存并退款，这些状态只能由支付与库存事件驱动
+	if to != model.OrderStatusProcessing {
+		return nil, status.Errorf(codes.FailedPrecondition, "orders cannot be moved to %s manually", to)
+	}
+	if actor == "" {
+		actor = "api"
+	}
+	return s.transition(ctx, id, to, actor, reason, expectedVersion)
+}
+
+// CancelOrder 发起订单取消：订单先进入 cancelling 状态并发布 order.cancelled 事件，
+// 待库存归还与支付退款均确认后才最终变为 cancelled
+func (s *OrderService) CancelOrder(ctx context.Context, id string, actor string, reason string) (*model.Order, error) {
+	order, err := s.loadOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch order.Status {
+	case model.OrderStatusCancelling, model.OrderStatusCancelled:
+		// 重复取消直接返回当前订单
+		return order, nil
+	}
+
+	if actor == "" {
+		actor = "customer:" + order.UserID.String()
+	}
+	event, err := messaging.NewOrderCancelledEvent(order, reason)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, id, model.OrderStatusCancelling, actor, reason, 0, event)
+}
+
+// ExpireOrders 取消截止时间已过仍未支付的订单，每次最多处理 limit 个，返回实际取消的数量。
+// 订单进入 cancelling 状态并发布 order.expired 事件，由下游服务归还库存、撤销支付
+func (s *OrderService) ExpireOrders(ctx context.Context, limit int64) (int, error) {
+	orders, err := s.repo.ListExpired(ctx, time.Now().Format(time.RFC3339), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired orders: %v", err)
+	}
+
+	expired := 0
+	for _, order := range orders {
+		event, err := messaging.NewOrderExpiredEvent(order)
+		if err != nil {
+			return expired, err
+		}
+		// 以扫描时的版本号为前提条件更新，避免取消扫描之后刚完成支付的订单
+		ok, err := s.repo.Transition(ctx, order.ID.String(), order.Version, model.StatusChange{
+			From:      order.Status,
+			To:        model.OrderStatusCancelling,
+			Actor:     "order-scheduler",
+			Reason:    "payment deadline exceeded",
+			ChangedAt: time.Now().Format(time.RFC3339),
+		}, event)
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire order %s: %v", order.ID, err)
+		}
+		if !ok {
+			// 扫描之后订单状态已发生变化，留待下次扫描重新判断
+			continue
+		}
+		if updated, err := s.refreshCache(ctx, order.ID.String()); err != nil {
+			log.Printf("failed to refresh cache of expired order %s: %v", order.ID, err)
+		} else {
+			s.notifyStatusChange(updated)
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// ListStuckOrders 返回停留在非终态超过 stuckAfter 的订单，供对账任务核对下游服务的真实状态
+func (s *OrderService) ListStuckOrders(ctx context.Context, stuckAfter time.Duration, limit int64) ([]*model.Order, error) {
+	statuses := []model.OrderStatus{model.OrderStatusPending, model.OrderStatusProcessing, model.OrderStatusCancelling}
+	now := time.Now()
+	before := now.Add(-stuckAfter).Format(time.RFC3339)
+	orders, err := s.repo.ListStale(ctx, statuses, before, now.Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stuck orders: %v", err)
+	}
+	return orders, nil
+}
+
+// RepublishOrderCreated 重新发布 order.created 事件，库存服务与支付服务会跳过已处理过的订单并重新回传结果。
+// 订单在退避时间内不会再被对账
+func (s *OrderService) RepublishOrderCreated(ctx context.Context, order *model.Order) error {
+	event, err := messaging.NewOrderCreatedEvent(order)
+	if err != nil {
+		return err
+	}
+	return s.repo.ScheduleReconcile(ctx, order.ID.String(), nextReconcileAt(order.ReconcileAttempts), event)
+}
+
+// RepublishOrderCancelled 重新发布 order.cancelled 事件，促使下游服务重新回传补偿确认。
+// 订单在退避时间内不会再被对账
+func (s *OrderService) RepublishOrderCancelled(ctx context.Context, order *model.Order, reason string) error {
+	event, err := messaging.NewOrderCancelledEvent(order, reason)
+	if err != nil {
+		return err
+	}
+	return s.repo.ScheduleReconcile(ctx, order.ID.String(), nextReconcileAt(order.ReconcileAttempts), event)
+}
+
+// nextReconcileAt 返回已重新发布 attempts 次事件的订单下次对账的最早时间
+func nextReconcileAt(attempts int64) string {
+	backoff := maxReconcileBackoff
+	if attempts < 6 && reconcileBackoffBase<<attempts < maxReconcileBackoff {
+		backoff = reconcileBackoffBase << attempts
+	}
+	return time.Now().Add(backoff).Format(time.RFC3339)
+}
+
+// CompletePayment 处理支付完成事件，将订单置为 completed；
+// 若订单已进入取消流程，则重新发布 order.cancelled 以便支付服务对这笔迟到的支付退款
+func (s *OrderService) CompletePayment(ctx context.Context, orderID string) error {
+	_, err := s.transition(ctx, orderID, model.OrderStatusCompleted, "payment-service", "payment completed", 0)
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound:
+		log.Printf("ignore payment completed event for unknown order %s", orderID)
+		return nil
+	case codes.FailedPrecondition:
+		order, err := s.loadOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.Status == model.OrderStatusCancelling || order.Status == model.OrderStatusCancelled {
+			event, err := messaging.NewOrderCancelledEvent(order, "payment completed after cancellation")
+			if err != nil {
+				return err
+			}
+			return s.repo.AddEvents(ctx, event)
+		}
+		return nil
+	default:
+		return err
+	}
+}
+
+// FailOrder 处理库存不足事件，将仍在等待履约的订单置为 failed；
+// 订单已进入其他状态（如已被取消）时忽略
+func (s *OrderService) FailOrder(ctx context.Context, orderID string, reason string) error {
+	_, err := s.transition(ctx, orderID, model.OrderStatusFailed, "inventory-service", reason, 0)
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound, codes.FailedPrecondition:
+		log.Printf("ignore inventory insufficient event for order %s: %v", orderID, err)
+		return nil
+	default:
+		return err
+	}
+}
+
+// CancelUnfulfillableOrder 处理库存提交失败事件：订单已支付但预留的库存已过期归还，
+// 将订单置为 cancelling 并发布 order.cancelled，由支付服务退款、库存服务回传确认
+func (s *OrderService) CancelUnfulfillableOrder(ctx context.Context, orderID string, reason string) error {
+	_, err := s.CancelOrder(ctx, orderID, "inventory-service", reason)
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound, codes.FailedPrecondition:
+		log.Printf("ignore inventory commit failed event for order %s: %v", orderID, err)
+		return nil
+	default:
+		return err
+	}
+}
+
+// FailPayment 将扣款失败的订单置为 payment_failed，reasonCode 为支付服务回传的失败原因代码。
+// 订单已被取消或已完成时忽略该事件
+func (s *OrderService) FailPayment(ctx context.Context, orderID string, reasonCode string) error {
+	_, err := s.transition(ctx, orderID, model.OrderStatusPaymentFailed, "payment-service", "payment failed: "+reasonCode, 0)
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound, codes.FailedPrecondition:
+		log.Printf("ignore payment failed event for order %s: %v", orderID, err)
+		return nil
+	default:
+		return err
+	}
+}
+
+// ConfirmCompensation 记录下游服务的补偿确认，库存与支付均确认后将订单置为 cancelled
+func (s *OrderService) ConfirmCompensation(ctx context.Context, orderID string, compensation model.Compensation) error {
+	order, err := s.repo.ConfirmCompensation(ctx, orderID, compensation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			log.Printf("ignore %s confirmation for unknown order %s", compensation, orderID)
+			return nil
+		}
+		return err
+	}
+
+	if order.Status != model.OrderStatusCancelling || !order.StockReleased || !order.PaymentRefunded {
+		return nil
+	}
+
+	_, err = s.transition(ctx, orderID, model.OrderStatusCancelled, "order-service", "stock released and payment refunded", 0)
+	if status.Code(err) == codes.FailedPrecondition {
+		// 另一条确认消息已完成了取消
+		return nil
+	}
+	return err
+}
+
+// transition 按状态机规则将订单迁移到 to 并追加状态历史，非法迁移返回 FailedPrecondition。
+// 写入以读取时的版本号为前提条件：expectedVersion 为 0 时，若订单已被并发修改则重新读取后重试；
+// 调用方指定了 expectedVersion 时不重试，版本号不一致直接返回 Aborted。events 与状态变更在同一事务中写入发件箱
+func (s *OrderService) transition(ctx context.Context, id string, to model.OrderStatus, actor string, reason string, expectedVersion int64, events ...*model.OutboxEvent) (*model.Order, error) {
+	for attempt := 0; attempt < maxTransitionAttempts; attempt++ {
+		order, err := s.loadOrder(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if expectedVersion > 0 && order.Version != expectedVersion {
+			return nil, status.Errorf(codes.Aborted, "order %s version is %d, expected %d", id, order.Version, expectedVersion)
+		}
+		if !order.Status.CanTransitionTo(to) {
+			return nil, status.Errorf(codes.FailedPrecondition, "order %s cannot transition from %s to %s", id, order.Status, to)
+		}
+
+		ok, err := s.repo.Transition(ctx, id, order.Version, model.StatusChange{
+			From:      order.Status,
+			To:        to,
+			Actor:     actor,
+			Reason:    reason,
+			ChangedAt: time.Now().Format(time.RFC3339),
+		}, events...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update order status: %v", err)
+		}
+		if ok {
+			updated, err := s.refreshCache(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			s.notifyStatusChange(updated)
+			return updated, nil
+		}
+		if expectedVersion > 0 {
+			break
+		}
+	}
+	return nil, status.Errorf(codes.Aborted, "order %s was modified concurrently", id)
+}
+
+// WatchOrder 推送订单状态变更：先发送订单当前状态，之后每次状态变更调用一次 send，
+// 直到 ctx 被取消、send 返回错误或订单进入终态
+func (s *OrderService) WatchOrder(ctx context.Context, id string, send func(event *model.OrderEvent) error) error {
+	// 先订阅再读取当前状态，避免遗漏两者之间发生的变更
+	pubsub := s.redisClient.Subscribe(ctx, orderEventsChannel(id))
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to subscribe order events: %v", err)
+	}
+
+	order, err := s.loadOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := send(newOrderEvent(order)); err != nil {
+		return err
+	}
+	if order.Status.Terminal() {
+		return nil
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return status.Error(codes.Unavailable, "order event subscription closed")
+			}
+			var event model.OrderEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("failed to unmarshal order event: %v", err)
+				continue
+			}
+			if err := send(&event); err != nil {
+				return err
+			}
+			if event.Order.Status.Terminal() {
+				return nil
+			}
+		}
+	}
+}
+
+// notifyStatusChange 通过 Redis 发布订单的最近一次状态变更，推送给所有副本上的 WatchOrder 订阅者；
+// 推送失败不影响状态变更本身
+func (s *OrderService) notifyStatusChange(order *model.Order) {
+	if err := s.redisClient.Publish(orderEventsChannel(order.ID.String()), newOrderEvent(order)); err != nil {
+		log.Printf("failed to publish status change of order %s: %v", order.ID, err)
+	}
+}
+
+func newOrderEvent(order *model.Order) *model.OrderEvent {
+	event := &model.OrderEvent{Order: order}
+	if len(order.StatusHistory) > 0 {
+		event.Change = order.StatusHistory[len(order.StatusHistory)-1]
+	}
+	return event
+}
+
+func orderEventsChannel(id string) string {
+	return fmt.Sprintf("order_events_%s", id)
 }
 
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, id string, status model.OrderStatus) (*model.Order, error) {
-	if err := s.repo.UpdateStatus(ctx, id, status); err != nil {
+// loadOrder 从数据库读取订单，订单不存在时返回 NotFound
+func (s *OrderService) loadOrder(ctx context.Context, id string) (*model.Order, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(codes.NotFound, "order %s not found", id)
+		}
 		return nil, err
 	}
-	updatedOrder, err := s.repo.GetByID(ctx, id)
+	return order, nil
+}
+
+// refreshCache 从数据库重新读取订单并写入缓存
+func (s *OrderService) refreshCache(ctx context.Context, id string) (*model.Order, error) {
+	order, err := s.loadOrder(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	// 更新缓存
-	if err := s.redisClient.Set(fmt.Sprintf("order_%s", id), updatedOrder); err != nil {
+	if err := s.redisClient.Set(fmt.Sprintf("order_%s", id), order); err != nil {
 		return nil, fmt.Errorf("failed to update order in cache: %v", err)
 	}
-	// 获取更新后的订单
-	return updatedOrder, err
+	return order, nil
+}
+
+// ListOrders 按过滤条件分页查询订单，返回当前页订单和下一页的游标
+func (s *OrderService) ListOrders(ctx context.Context, filter model.OrderFilter, pageSize int32, pageToken string) ([]*model.Order, string, error) {
+	if filter.UserID != "" {
+		if _, err := uuid.Parse(filter.UserID); err != nil {
+			return nil, "", status.Errorf(codes.InvalidArgument, "invalid customer_id: %v", err)
+		}
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid status: %s", filter.Status)
+	}
+	var err error
+	if filter.CreatedAfter, err = normalizeTime(filter.CreatedAfter); err != nil {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid created_after: %v", err)
+	}
+	if filter.CreatedBefore, err = normalizeTime(filter.CreatedBefore); err != nil {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid created_before: %v", err)
+	}
+
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	cursor, err := decodePageToken(pageToken)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	// 多查一条用于判断是否还有下一页
+	orders, err := s.repo.List(ctx, filter, cursor, int64(pageSize)+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list orders: %v", err)
+	}
+	if len(orders) <= int(pageSize) {
+		return orders, "", nil
+	}
+
+	orders = orders[:pageSize]
+	last := orders[len(orders)-1]
+	nextToken, err := encodePageToken(&model.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID.String()})
+	if err != nil {
+		return nil, "", err
+	}
+	return orders, nextToken, nil
+}
+
+// normalizeTime 将 RFC3339 时间转换为本地时区格式，与订单中存储的 created_at 保持一致以便按字符串比较
+func normalizeTime(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", err
+	}
+	return t.Local().Format(time.RFC3339), nil
+}
+
+func encodePageToken(cursor *model.OrderCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode page token: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageToken(token string) (*model.OrderCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor model.OrderCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.CreatedAt == "" || cursor.ID == "" {
+		return nil, fmt.Errorf("incomplete cursor")
+	}
+	return &cursor, nil
 }
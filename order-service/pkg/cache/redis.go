@@ -38,3 +38,73 @@ func (r *RedisClient) Set(key string, value interface{}) error {
 	}
 	return r.client.Set(context.Background(), key, jsonData, 24*time.Hour).Err()
 }
+
+// SetNX 仅当 key 不存在时写入，返回是否写入成功
+func (r *RedisClient) SetNX(key string, value interface{}, ttl time.Duration) (bool, error) {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value to JSON: %v", err)
+	}
+	return r.client.SetNX(context.Background(), key, jsonData, ttl).Result()
+}
+
+// SetWithTTL 写入 key 并指定过期时间
+func (r *RedisClient) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value to JSON: %v", err)
+	}
+	return r.client.Set(context.Background(), key, jsonData, ttl).Err()
+}
+
+func (r *RedisClient) Del(key string) error {
+	return r.client.Del(context.Background(), key).Err()
+}
+
+// Publish 将 value 序列化为 JSON 后发布到 channel，所有副本上订阅了该 channel 的连接都会收到
+func (r *RedisClient) Publish(channel string, value interface{}) error {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value to JSON: %v", err)
+	}
+	return r.client.Publish(context.Background(), channel, jsonData).Err()
+}
+
+// Subscribe 订阅 channel，调用方负责在使用完毕后关闭返回的 PubSub
+func (r *RedisClient) Subscribe(ctx context.Context, channel string) *redis.PubSub {
+	return r.client.Subscribe(ctx, channel)
+}
+
+// acquireLeaseScript 租约不存在时创建；已由 owner 持有时续期
+var acquireLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseLeaseScript 仅当租约仍由 owner 持有时删除
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLease 尝试以 owner 身份获取或续期租约，返回当前是否持有租约。
+// 多个副本竞争同一租约时同一时刻只有一个能获得，持有者宕机后租约在 ttl 后自动失效
+func (r *RedisClient) AcquireLease(key string, owner string, ttl time.Duration) (bool, error) {
+	result, err := acquireLeaseScript.Run(context.Background(), r.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// ReleaseLease 释放 owner 持有的租约，租约已被他人持有时不做任何操作
+func (r *RedisClient) ReleaseLease(key string, owner string) error {
+	return releaseLeaseScript.Run(context.Background(), r.client, []string{key}, owner).Err()
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/spf13/viper"
 	"log"
+	"time"
 )
 
 type Config struct {
@@ -12,6 +13,9 @@ type Config struct {
 	Consul   ConsulConfig   `mapstructure:"consul"`
 	Jaeger   JaegerConfig   `mapstructure:"jaeger"`
 	Redis    RedisConfig    `mapstructure:"redis"`
+	Order    OrderConfig    `mapstructure:"order"`
+	// Dependencies 订单服务调用的下游服务在 Consul 中注册的名称
+	Dependencies DependenciesConfig `mapstructure:"dependencies"`
 }
 
 type ServerConfig struct {
@@ -30,6 +34,8 @@ type MongoConfig struct {
 	Database string `mapstructure:"database"`
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
+	// ReplicaSet 副本集名称，订单与发件箱事件的原子写入依赖副本集提供的事务
+	ReplicaSet string `mapstructure:"replica_set"`
 }
 
 type RabbitMQConfig struct {
@@ -60,6 +66,22 @@ type RedisConfig struct {
 	Password string `mapstructure:"password"`
 }
 
+type OrderConfig struct {
+	// PaymentTimeout 下单后等待支付的时长，超时未支付的订单会被自动取消
+	PaymentTimeout time.Duration `mapstructure:"payment_timeout"`
+	// ExpirySweepInterval 扫描超时订单的间隔
+	ExpirySweepInterval time.Duration `mapstructure:"expiry_sweep_interval"`
+	// ReconcileInterval 对账任务的执行间隔
+	ReconcileInterval time.Duration `mapstructure:"reconcile_interval"`
+	// ReconcileAfter 订单停留在非终态超过该时长才会被对账
+	ReconcileAfter time.Duration `mapstructure:"reconcile_after"`
+}
+
+type DependenciesConfig struct {
+	Inventory string `mapstructure:"inventory"`
+	Payment   string `mapstructure:"payment"`
+}
+
 func NewConfig(path string) (*Config, error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName("config")
@@ -8,19 +8,20 @@ import (
 	"github.com/rabbitmq/amqp091-go"
 	"log"
 	"order-microsystem/order-service/internal/domain/model"
-	"order-microsystem/order-service/internal/domain/repository/mongodb"
 	"order-microsystem/order-service/pkg/config"
 	"time"
 )
 
+// queuePrefix 本服务所有消费队列名称的前缀
+const queuePrefix = "order-service."
+
 type RabbitMQ struct {
 	conn   *amqp091.Connection
 	ch     *amqp091.Channel
 	config *config.RabbitMQConfig
-	repo   *mongodb.OrderRepository
 }
 
-func NewRabbitMQ(config *config.RabbitMQConfig, repo *mongodb.OrderRepository) (*RabbitMQ, error) {
+func NewRabbitMQ(config *config.RabbitMQConfig) (*RabbitMQ, error) {
 	url := fmt.Sprintf("amqp://%s:%s@%s:%d", config.Username, config.Password, config.Host, config.Port)
 
 	var conn *amqp091.Connection
@@ -59,7 +60,6 @@ func NewRabbitMQ(config *config.RabbitMQConfig, repo *mongodb.OrderRepository) (
 		conn:   conn,
 		ch:     channel,
 		config: config,
-		repo:   repo,
 	}, nil
 }
 
@@ -70,7 +70,8 @@ func (rmq *RabbitMQ) Close() error {
 	return rmq.conn.Close()
 }
 
-func (rmq *RabbitMQ) PublishOrderCreated(ctx context.Context, order *model.Order) error {
+// NewOrderCreatedEvent 构建订单创建事件，写入发件箱后由 relay 投递
+func NewOrderCreatedEvent(order *model.Order) (*model.OutboxEvent, error) {
 	// 构建订单创建事件的消息体
 	event := map[string]interface{}{
 		"event_type":  "order_created", // 事件类型标识
@@ -82,71 +83,91 @@ func (rmq *RabbitMQ) PublishOrderCreated(ctx context.Context, order *model.Order
 		"created_at":  order.CreatedAt, // 订单创建时间
 	}
 
+	return newOutboxEvent("order.created", event)
+}
+
+// NewOrderCancelledEvent 构建订单取消事件，库存服务据此归还库存，支付服务据此退款
+func NewOrderCancelledEvent(order *model.Order, reason string) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type":  "order_cancelled",
+		"order_id":    order.ID,
+		"user_id":     order.UserID,
+		"products":    order.Items,
+		"total_price": order.TotalPrice,
+		"reason":      reason,
+	}
+
+	return newOutboxEvent("order.cancelled", event)
+}
+
+// NewOrderExpiredEvent 构建订单超时事件，库存服务与支付服务按取消订单的方式归还库存、撤销支付
+func NewOrderExpiredEvent(order *model.Order) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type":  "order_expired",
+		"order_id":    order.ID,
+		"user_id":     order.UserID,
+		"products":    order.Items,
+		"total_price": order.TotalPrice,
+		"expires_at":  order.ExpiresAt,
+	}
+
+	return newOutboxEvent("order.expired", event)
+}
+
+// newOutboxEvent 将事件序列化为 JSON 并生成唯一的事件ID，该ID会作为消息的 MessageId 投递
+func newOutboxEvent(routingKey string, event interface{}) (*model.OutboxEvent, error) {
 	// 将事件对象序列化为JSON格式
 	body, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %v", err)
-	}
-
-	// 设置5秒超时上下文，防止消息发布阻塞
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel() // 确保在函数退出时取消上下文
-
-	// 发布消息到RabbitMQ
-	err = rmq.ch.PublishWithContext(ctx,
-		rmq.config.Exchange, // 使用配置中指定的交换机
-		"order.created",     // 路由键(routing key)
-		false,               // 不强制(mandatory)
-		false,               // 不立即(immediate)
-		amqp091.Publishing{
-			ContentType: "application/json", // 消息内容类型
-			Body:        body,               // 消息体(JSON格式)
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to publish message: %v", err)
+		return nil, fmt.Errorf("failed to marshal event: %v", err)
 	}
 
-	return nil
+	return &model.OutboxEvent{
+		ID:         uuid.New().String(),
+		RoutingKey: routingKey,
+		Payload:    string(body),
+		CreatedAt:  time.Now(),
+	}, nil
 }
 
-func (rmq *RabbitMQ) ConsumePaymentCompleted() {
-	// 声明一个匿名队列
+// consume 声明持久化的具名队列并绑定路由键后开始消费。服务停机期间投递的消息会保留在队列中，
+// 多个副本共享同一队列时每条消息只会被其中一个副本处理
+func (rmq *RabbitMQ) consume(name string, routingKeys ...string) (<-chan amqp091.Delivery, error) {
 	q, err := rmq.ch.QueueDeclare(
-		"",    // 队列名称为空，RabbitMQ会自动生成一个唯一的名称
-		false, // 非持久化
-		false, // 不自动删除
-		true,  // 排他性，只对当前连接可见
-		false, // 不等待
-		nil,   // 额外参数
+		queuePrefix+name, // 队列名称
+		true,             // 持久化
+		false,            // 不自动删除
+		false,            // 非排他性
+		false,            // 不等待
+		nil,              // 额外参数
 	)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("failed to declare queue: %v", err)
 	}
 
 	// 绑定队列到交换机，指定路由键
-	err = rmq.ch.QueueBind(
-		q.Name,              // 队列名称
-		"payment.completed", // 路由键
-		rmq.config.Exchange, // 交换机名称
-		false,
-		nil,
-	)
+	for _, routingKey := range routingKeys {
+		if err := rmq.ch.QueueBind(q.Name, routingKey, rmq.config.Exchange, false, nil); err != nil {
+			return nil, fmt.Errorf("failed to bind queue: %v", err)
+		}
+	}
+
+	// 消费消息，手动确认
+	return rmq.ch.Consume(q.Name, "", false, false, false, false, nil)
+}
+
+// PaymentHandler 处理支付服务回传的支付结果
+type PaymentHandler interface {
+	CompletePayment(ctx context.Context, orderID string) error
+	FailPayment(ctx context.Context, orderID string, reasonCode string) error
+}
+
+func (rmq *RabbitMQ) ConsumePaymentCompleted(handler PaymentHandler) {
+	msgs, err := rmq.consume("payment.completed", "payment.completed")
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	// 消费消息
-	msgs, err := rmq.ch.Consume(
-		q.Name, // 队列名称
-		"",     // 消费者标识
-		false,  // 手动确认
-		false,  // 非排他性
-		false,  // 不等待
-		false,  // 额外参数
-		nil,    // 额外参数
-	)
-
 	for msg := range msgs {
 		var event struct {
 			EventType string    `json:"event_type"`
@@ -154,17 +175,175 @@ func (rmq *RabbitMQ) ConsumePaymentCompleted() {
 			OrderID   uuid.UUID `json:"order_id"`
 		}
 		if err := json.Unmarshal(msg.Body, &event); err != nil {
-			log.Fatal(err.Error())
-			msg.Nack(false, true) // 重试
+			// 消息格式错误，重试无意义，直接丢弃
+			log.Printf("failed to unmarshal payment completed event: %v", err)
+			msg.Nack(false, false)
 			continue
 		}
 
 		// 更新订单状态
-		if err := rmq.repo.UpdateStatus(context.Background(), event.OrderID.String(), model.OrderStatusCompleted); err != nil {
-			log.Fatalf(err.Error())
+		if err := handler.CompletePayment(context.Background(), event.OrderID.String()); err != nil {
+			log.Printf("failed to complete order %s: %v", event.OrderID, err)
 			msg.Nack(false, true) //重试
 			continue
 		}
 		msg.Ack(false) // 确认消息
 	}
 }
+
+// ConsumePaymentFailed 消费支付失败(payment.failed)事件，将订单置为 payment_failed
+func (rmq *RabbitMQ) ConsumePaymentFailed(handler PaymentHandler) {
+	msgs, err := rmq.consume("payment.failed", "payment.failed")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	for msg := range msgs {
+		var event struct {
+			EventType  string    `json:"event_type"`
+			OrderID    uuid.UUID `json:"order_id"`
+			ReasonCode string    `json:"reason_code"`
+		}
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			// 消息格式错误，重试无意义，直接丢弃
+			log.Printf("failed to unmarshal payment failed event: %v", err)
+			msg.Nack(false, false)
+			continue
+		}
+
+		if err := handler.FailPayment(context.Background(), event.OrderID.String(), event.ReasonCode); err != nil {
+			log.Printf("failed to mark payment failed for order %s: %v", event.OrderID, err)
+			msg.Nack(false, true) // 重试
+			continue
+		}
+		msg.Ack(false)
+	}
+}
+
+// InventoryHandler 处理库存服务回传的扣减失败结果
+type InventoryHandler interface {
+	FailOrder(ctx context.Context, orderID string, reason string) error
+}
+
+// ConsumeInventoryInsufficient 消费库存不足(inventory.insufficient)事件，将订单置为失败
+func (rmq *RabbitMQ) ConsumeInventoryInsufficient(handler InventoryHandler) {
+	msgs, err := rmq.consume("inventory.insufficient", "inventory.insufficient")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	for msg := range msgs {
+		var event struct {
+			EventType string    `json:"event_type"`
+			OrderID   uuid.UUID `json:"order_id"`
+			// Reason 为 sold_out 表示秒杀库存已售罄
+			Reason    string `json:"reason"`
+			Shortages []struct {
+				ProductID int64 `json:"product_id"`
+				Requested int64 `json:"requested"`
+				Available int64 `json:"available"`
+			} `json:"shortages"`
+		}
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			// 消息格式错误，重试无意义，直接丢弃
+			log.Printf("failed to unmarshal inventory insufficient event: %v", err)
+			msg.Nack(false, false)
+			continue
+		}
+
+		reason := "insufficient stock"
+		if event.Reason == "sold_out" {
+			reason = "sold out"
+		}
+		for i, shortage := range event.Shortages {
+			separator := ", "
+			if i == 0 {
+				separator = ": "
+			}
+			reason += fmt.Sprintf("%sproduct %d requested %d available %d", separator, shortage.ProductID, shortage.Requested, shortage.Available)
+		}
+
+		if err := handler.FailOrder(context.Background(), event.OrderID.String(), reason); err != nil {
+			log.Printf("failed to fail order %s: %v", event.OrderID, err)
+			msg.Nack(false, true) // 重试
+			continue
+		}
+		msg.Ack(false)
+	}
+}
+
+// CommitFailedHandler 处理库存服务无法为已支付订单提交库存的结果
+type CommitFailedHandler interface {
+	CancelUnfulfillableOrder(ctx context.Context, orderID string, reason string) error
+}
+
+// ConsumeInventoryCommitFailed 消费库存提交失败(inventory.commit_failed)事件，取消已无库存可发货的订单并退款
+func (rmq *RabbitMQ) ConsumeInventoryCommitFailed(handler CommitFailedHandler) {
+	msgs, err := rmq.consume("inventory.commit_failed", "inventory.commit_failed")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	for msg := range msgs {
+		var event struct {
+			EventType string    `json:"event_type"`
+			OrderID   uuid.UUID `json:"order_id"`
+			Reason    string    `json:"reason"`
+		}
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			// 消息格式错误，重试无意义，直接丢弃
+			log.Printf("failed to unmarshal inventory commit failed event: %v", err)
+			msg.Nack(false, false)
+			continue
+		}
+
+		if err := handler.CancelUnfulfillableOrder(context.Background(), event.OrderID.String(), event.Reason); err != nil {
+			log.Printf("failed to cancel unfulfillable order %s: %v", event.OrderID, err)
+			msg.Nack(false, true) // 重试
+			continue
+		}
+		msg.Ack(false)
+	}
+}
+
+// CompensationHandler 处理取消订单时下游服务回传的补偿确认
+type CompensationHandler interface {
+	ConfirmCompensation(ctx context.Context, orderID string, compensation model.Compensation) error
+}
+
+// ConsumeCompensations 消费库存释放(inventory.released)与支付撤销(payment.cancelled)确认消息
+func (rmq *RabbitMQ) ConsumeCompensations(handler CompensationHandler) {
+	compensations := map[string]model.Compensation{
+		"inventory.released": model.CompensationStock,
+		"payment.cancelled":  model.CompensationPayment,
+	}
+
+	routingKeys := make([]string, 0, len(compensations))
+	for routingKey := range compensations {
+		routingKeys = append(routingKeys, routingKey)
+	}
+	msgs, err := rmq.consume("compensations", routingKeys...)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	for msg := range msgs {
+		var event struct {
+			EventType string    `json:"event_type"`
+			OrderID   uuid.UUID `json:"order_id"`
+		}
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			// 消息格式错误，重试无意义，直接丢弃
+			log.Printf("failed to unmarshal %s event: %v", msg.RoutingKey, err)
+			msg.Nack(false, false)
+			continue
+		}
+
+		if err := handler.ConfirmCompensation(context.Background(), event.OrderID.String(), compensations[msg.RoutingKey]); err != nil {
+			log.Printf("failed to confirm %s for order %s: %v", msg.RoutingKey, event.OrderID, err)
+			msg.Nack(false, true) // 重试
+			continue
+		}
+		msg.Ack(false)
+	}
+}
@@ -0,0 +1,8 @@
+package messaging
+
+import "order-microsystem/outbox"
+
+// NewOutboxRelay 创建投递本服务发件箱事件的 relay
+func NewOutboxRelay(rmq *RabbitMQ, store outbox.Store) *outbox.Relay {
+	return outbox.NewRelay(rmq.conn, rmq.config.Exchange, store)
+}
@@ -16,4 +16,10 @@ var (
 		Help:    "gRPC request duration",
 		Buckets: []float64{0.1, 0.3, 0.5, 1.0, 2.5, 5.0},
 	}, []string{"service", "method", "code"}) // 3个标签
+
+	// ReconcilerActions 对账任务对卡住订单采取的处理动作
+	ReconcilerActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_reconciler_actions_total",
+		Help: "Actions taken by the order reconciler",
+	}, []string{"action"})
 )
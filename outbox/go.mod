@@ -0,0 +1,5 @@
+module order-microsystem/outbox
+
+go 1.23.8
+
+require github.com/rabbitmq/amqp091-go v1.10.0
@@ -0,0 +1,166 @@
+// Package outbox 实现各服务共用的发件箱 relay：领取发件箱中未发送的事件并投递到 RabbitMQ
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/rabbitmq/amqp091-go"
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	relayInterval  = time.Second
+	relayBatchSize = 100
+	// ClaimLease 事件被领取后的租约时长，领取的副本在租约内未能投递时其他副本可以重新领取
+	ClaimLease = time.Minute
+	// publishTimeout 单条事件等待 broker 确认的超时时间
+	publishTimeout = 5 * time.Second
+)
+
+// Event 是 relay 投递的发件箱事件
+type Event struct {
+	ID         string
+	RoutingKey string
+	Payload    string
+	CreatedAt  time.Time
+}
+
+// Store 是 relay 领取和标记发件箱事件所需的存储接口
+type Store interface {
+	// ClaimPending 按写入顺序领取至多 limit 条未发送的事件，领取期限为 lease。
+	// 其他副本领取且仍在租约内的事件不会被返回，owner 自己此前领取但尚未发送的事件会被再次返回
+	ClaimPending(ctx context.Context, owner string, lease time.Duration, limit int) ([]*Event, error)
+	// MarkSent 将事件标记为已发送
+	MarkSent(ctx context.Context, id string) error
+}
+
+// Relay 周期性地将发件箱中未发送的事件投递到 RabbitMQ，仅在 broker 确认(publisher confirm)后才标记为已发送，
+// 保证事件至少投递一次。多副本部署时每个副本只投递自己领取的事件，同一事件不会被多个副本同时投递
+type Relay struct {
+	conn     *amqp091.Connection
+	exchange string
+	store    Store
+	owner    string
+	ch       *amqp091.Channel
+}
+
+func NewRelay(conn *amqp091.Connection, exchange string, store Store) *Relay {
+	return &Relay{
+		conn:     conn,
+		exchange: exchange,
+		store:    store,
+		owner:    newOwner(),
+	}
+}
+
+// newOwner 生成标识当前副本的领取者ID
+func newOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Run 持续投递发件箱事件，直到 ctx 被取消
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.relayPending(ctx); err != nil {
+			log.Printf("outbox relay: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if r.ch != nil {
+				r.ch.Close()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relayPending(ctx context.Context) error {
+	events, err := r.store.ClaimPending(ctx, r.owner, ClaimLease, relayBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim pending events: %v", err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	ch, err := r.channel()
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		// 按顺序投递，任一事件失败则停止本轮，下一轮从该事件重新开始
+		if err := r.publish(ctx, ch, event); err != nil {
+			return fmt.Errorf("failed to publish event %s: %v", event.ID, err)
+		}
+		if err := r.store.MarkSent(ctx, event.ID); err != nil {
+			return fmt.Errorf("failed to mark event %s as sent: %v", event.ID, err)
+		}
+	}
+	return nil
+}
+
+func (r *Relay) publish(ctx context.Context, ch *amqp091.Channel, event *Event) error {
+	// 设置超时上下文，防止等待确认阻塞
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+		r.exchange,
+		event.RoutingKey,
+		false,
+		false,
+		amqp091.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp091.Persistent,
+			MessageId:    event.ID,
+			Timestamp:    event.CreatedAt,
+			Body:         []byte(event.Payload),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return fmt.Errorf("broker nacked the message")
+	}
+	return nil
+}
+
+// channel 返回处于 confirm 模式的专用通道，通道关闭后自动重建
+func (r *Relay) channel() (*amqp091.Channel, error) {
+	if r.ch != nil && !r.ch.IsClosed() {
+		return r.ch, nil
+	}
+
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a channel: %v", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %v", err)
+	}
+	r.ch = ch
+	return ch, nil
+}
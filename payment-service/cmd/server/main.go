@@ -5,6 +5,7 @@ import (
 	"log"
 	"order-microsystem/payment-service/internal/controller"
 	"order-microsystem/payment-service/internal/domain/repository"
+	"order-microsystem/payment-service/internal/provider"
 	"order-microsystem/payment-service/internal/server"
 	"order-microsystem/payment-service/internal/service"
 	"order-microsystem/payment-service/pkg/config"
@@ -40,17 +41,29 @@ func main() {
 		log.Fatalf("failed to call AutoMigration: %v", err)
 	}
 
-	// 调用 messaging.NewRabbitMQ 函数初始化 RabbitMQ 连接，传入 RabbitMQ 配置和仓库实例。
+	// 按配置创建支付渠道，扣款与退款都通过该渠道完成。
+	paymentProvider, err := provider.NewProvider(&cfg.Payment)
+	if err != nil {
+		log.Fatalf("failed to create payment provider: %v", err)
+	}
+
+	// 调用 messaging.NewRabbitMQ 函数初始化 RabbitMQ 连接，传入 RabbitMQ 配置、仓库实例和支付渠道。
 	// 若连接失败，使用 log.Fatalf 输出错误信息并终止程序。
-	rabbitMQ, err := messaging.NewRabbitMQ(&cfg.RabbitMQ, repo)
+	rabbitMQ, err := messaging.NewRabbitMQ(&cfg.RabbitMQ, repo, paymentProvider, cfg.Payment.Timeout)
 	if err != nil {
 		log.Fatalf("failed to connect RabbitMQ: %v", err)
 	}
 	// 延迟关闭 RabbitMQ 连接，在函数返回时执行 Close 方法。
 	// 注意：这里 Close 方法可能返回错误，需要处理，当前未处理。
 	defer rabbitMQ.Close()
+	// 启动发件箱 relay，将已落库的领域事件投递到 RabbitMQ。
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go messaging.NewOutboxRelay(rabbitMQ, repo).Run(relayCtx)
 	// 启动一个 goroutine 来消费库存锁定消息。
 	go rabbitMQ.ConsumeInventoryLocked()
+	// 启动一个 goroutine 来消费订单取消消息，对已支付的订单退款。
+	go rabbitMQ.ConsumeOrderCancelled()
 
 	// 调用 tracing.InitTracer 函数初始化分布式追踪器，传入配置信息。
 	// 若初始化失败，使用 log.Fatalf 输出错误信息并终止程序。
@@ -65,8 +78,8 @@ func main() {
 		}
 	}()
 
-	// 创建支付服务实例，传入数据库仓库实例。
-	paymentService := service.NewPaymentService(repo)
+	// 创建支付服务实例，传入数据库仓库实例和支付渠道。
+	paymentService := service.NewPaymentService(repo, paymentProvider)
 	// 创建支付控制器实例，传入支付服务实例。
 	paymentController := controller.NewPaymentController(paymentService)
 
@@ -3,6 +3,7 @@ module order-microsystem/payment-service
 go 1.23.8
 
 require (
+	order-microsystem/outbox v0.0.0
 	github.com/google/uuid v1.6.0
 	github.com/hashicorp/consul/api v1.32.0
 	github.com/prometheus/client_golang v1.22.0
@@ -65,3 +66,5 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace order-microsystem/outbox => ../outbox
@@ -3,11 +3,19 @@ package controller
 import (
 	"context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"log"
+	"order-microsystem/payment-service/internal/domain/model"
 	"order-microsystem/payment-service/internal/service"
 	pb "order-microsystem/payment-service/pkg/proto/payment"
+	"time"
 )
 
+// idempotencyKeyMetadata 携带幂等键的 gRPC 元数据名称
+const idempotencyKeyMetadata = "idempotency-key"
+
 type PaymentController struct {
 	pb.UnimplementedPaymentServiceServer
 	svc *service.PaymentService
@@ -23,36 +31,123 @@ func RegisterPaymentController(server *grpc.Server, svc *PaymentController) {
 	pb.RegisterPaymentServiceServer(server, svc)
 }
 
+// GetPayment 按支付ID查询支付记录
 func (c *PaymentController) GetPayment(ctx context.Context, req *pb.GetPaymentRequest) (*pb.GetPaymentResponse, error) {
-	payment, err := c.svc.GetPayment(req.UserId)
+	payment, err := c.svc.GetPayment(req.PaymentId)
 	if err != nil {
-		log.Fatalf("GetPayment failed: %v", err)
+		log.Printf("GetPayment failed: %v", err)
+		return nil, err
 	}
 	return &pb.GetPaymentResponse{
-		Payment: &pb.Payment{
-			UserId:     payment.UserID.String(),
-			OrderId:    payment.OrderID.String(),
-			PaymentId:  payment.PaymentID.String(),
-			TotalPrice: payment.TotalPrice,
-		},
+		Payment: convertToProto(payment),
+	}, nil
+}
+
+// GetPaymentByOrder 查询订单的支付记录
+func (c *PaymentController) GetPaymentByOrder(ctx context.Context, req *pb.GetPaymentByOrderRequest) (*pb.GetPaymentByOrderResponse, error) {
+	payment, err := c.svc.GetPaymentByOrder(req.OrderId)
+	if err != nil {
+		log.Printf("GetPaymentByOrder failed: %v", err)
+		return nil, err
+	}
+	return &pb.GetPaymentByOrderResponse{
+		Payment: convertToProto(payment),
 	}, nil
 }
 
+// GetAllPayment 按过滤条件分页查询支付记录
 func (c *PaymentController) GetAllPayment(ctx context.Context, req *pb.GetAllPaymentRequest) (*pb.GetAllPaymentResponse, error) {
-	payments, err := c.svc.GetAllPayment(req.UserId)
+	filter := model.PaymentFilter{
+		UserID: req.UserId,
+		Status: model.PaymentStatus(req.Status),
+	}
+	var err error
+	if filter.CreatedAfter, err = parseTime(req.CreatedAfter); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid created_after: %v", err)
+	}
+	if filter.CreatedBefore, err = parseTime(req.CreatedBefore); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid created_before: %v", err)
+	}
+
+	payments, nextPageToken, err := c.svc.GetAllPayment(filter, req.PageSize, req.PageToken)
 	if err != nil {
-		log.Fatalf("GetAllPayment failed: %v", err)
+		log.Printf("GetAllPayment failed: %v", err)
+		return nil, err
 	}
-	var paymentsResp []*pb.Payment
+	paymentsResp := make([]*pb.Payment, 0, len(payments))
 	for _, payment := range payments {
-		paymentsResp = append(paymentsResp, &pb.Payment{
-			UserId:     payment.UserID.String(),
-			OrderId:    payment.OrderID.String(),
-			PaymentId:  payment.PaymentID.String(),
-			TotalPrice: payment.TotalPrice,
-		})
+		paymentsResp = append(paymentsResp, convertToProto(payment))
 	}
 	return &pb.GetAllPaymentResponse{
-		Payments: paymentsResp,
+		Payments:      paymentsResp,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// RefundPayment 退回支付的部分或全部金额
+func (c *PaymentController) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest) (*pb.RefundPaymentResponse, error) {
+	// 网关通过 gRPC 元数据透传客户端的 Idempotency-Key
+	var idempotencyKey string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+			idempotencyKey = values[0]
+		}
+	}
+
+	payment, refund, err := c.svc.RefundPayment(ctx, req.PaymentId, req.Amount, req.Reason, idempotencyKey)
+	if err != nil {
+		log.Printf("RefundPayment failed: %v", err)
+		return nil, err
+	}
+	return &pb.RefundPaymentResponse{
+		Refund: &pb.Refund{
+			RefundId:  refund.RefundID.String(),
+			PaymentId: refund.PaymentID.String(),
+			Amount:    refund.Amount,
+			Reason:    refund.Reason,
+			CreatedAt: refund.CreatedAt.Format(time.RFC3339),
+			Status:    string(refund.Status),
+		},
+		Payment: convertToProto(payment),
 	}, nil
 }
+
+func convertToProto(payment *model.PaymentModel) *pb.Payment {
+	return &pb.Payment{
+		UserId:              payment.UserID.String(),
+		OrderId:             payment.OrderID.String(),
+		PaymentId:           payment.PaymentID.String(),
+		TotalPrice:          payment.TotalPrice,
+		Status:              string(payment.Status),
+		FailureCode:         payment.FailureCode,
+		RefundedAmount:      payment.RefundedAmount,
+		CreatedAt:           payment.CreatedAt.Format(time.RFC3339),
+		AuthorizedAt:        formatTime(payment.AuthorizedAt),
+		CapturedAt:          formatTime(payment.CapturedAt),
+		FailedAt:            formatTime(payment.FailedAt),
+		PartiallyRefundedAt: formatTime(payment.PartiallyRefundedAt),
+		RefundedAt:          formatTime(payment.RefundedAt),
+		Provider:            payment.Provider,
+		TransactionId:       payment.TransactionID,
+	}
+}
+
+// formatTime 将可选的时间格式化为 RFC3339，未设置时返回空字符串
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseTime 解析 RFC3339 格式的可选时间，空字符串返回 nil
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -3,12 +3,100 @@ package model
 import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"time"
 )
 
+// PaymentStatus 支付记录的状态，依次为 pending -> authorized -> captured，
+// 扣款成功后可退款为 partially_refunded 或 refunded，授权或扣款被拒绝时为 failed
+type PaymentStatus string
+
+const (
+	// PaymentStatusPending 已收到扣款请求，尚未得到支付渠道的授权
+	PaymentStatusPending PaymentStatus = "pending"
+	// PaymentStatusAuthorized 支付渠道已冻结支付金额，尚未扣款
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	// PaymentStatusCaptured 支付渠道已扣款
+	PaymentStatusCaptured PaymentStatus = "captured"
+	// PaymentStatusFailed 授权或扣款失败，FailureCode 记录失败原因
+	PaymentStatusFailed PaymentStatus = "failed"
+	// PaymentStatusPartiallyRefunded 已退回部分金额
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	// PaymentStatusRefunded 已退回全部金额
+	PaymentStatusRefunded PaymentStatus = "refunded"
+)
+
+// Valid 判断状态是否为已定义的支付状态
+func (s PaymentStatus) Valid() bool {
+	switch s {
+	case PaymentStatusPending, PaymentStatusAuthorized, PaymentStatusCaptured, PaymentStatusFailed,
+		PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// Paid 判断支付是否已扣款，包括之后发生了退款的支付
+func (s PaymentStatus) Paid() bool {
+	return s == PaymentStatusCaptured || s == PaymentStatusPartiallyRefunded || s == PaymentStatusRefunded
+}
+
+// Refundable 判断支付是否还可以退款
+func (s PaymentStatus) Refundable() bool {
+	return s.Paid() && s != PaymentStatusRefunded
+}
+
 type PaymentModel struct {
 	gorm.Model
-	PaymentID  uuid.UUID `gorm:"type:varchar(128);not null;comment:支付ID"`
-	OrderID    uuid.UUID `gorm:"type:varchar(128);not null;comment:订单ID"`
-	UserID     uuid.UUID `gorm:"type:varchar(128);not null;comment:用户ID"`
-	TotalPrice int64     `gorm:"type:bigint;not null;comment:支付总金额"`
+	PaymentID  uuid.UUID  `gorm:"type:varchar(128);not null;comment:支付ID"`
+	OrderID    uuid.UUID  `gorm:"type:varchar(128);not null;uniqueIndex:uk_order_id;comment:订单ID"`
+	UserID     uuid.UUID  `gorm:"type:varchar(128);not null;comment:用户ID"`
+	TotalPrice int64      `gorm:"type:bigint;not null;comment:支付总金额"`
+	RefundedAt *time.Time `gorm:"comment:退款时间"`
+	// RefundedAmount 累计退款金额，退款记录见 RefundModel；累计退款达到支付金额时记录 RefundedAt
+	RefundedAmount int64 `gorm:"type:bigint;not null;default:0;comment:累计退款金额"`
+
+	// Provider 与 TransactionID 为扣款的支付渠道及渠道侧交易号，早于渠道接入的支付记录为空
+	Provider      string `gorm:"type:varchar(32);not null;default:'';comment:支付渠道"`
+	TransactionID string `gorm:"type:varchar(128);not null;default:'';comment:渠道交易号"`
+
+	Status PaymentStatus `gorm:"type:varchar(32);not null;default:'pending';index:idx_status;comment:支付状态"`
+	// FailureCode 扣款失败的原因代码，如支付渠道返回的拒绝原因
+	FailureCode string `gorm:"type:varchar(64);not null;default:'';comment:失败原因代码"`
+
+	// 进入各状态的时间，pending 为 CreatedAt，refunded 为 RefundedAt
+	AuthorizedAt        *time.Time `gorm:"comment:授权时间"`
+	CapturedAt          *time.Time `gorm:"comment:扣款时间"`
+	FailedAt            *time.Time `gorm:"comment:失败时间"`
+	PartiallyRefundedAt *time.Time `gorm:"comment:首次部分退款时间"`
+}
+
+// SetStatus 将支付置为 status 并记录进入该状态的时间，已记录的时间不会被覆盖
+func (p *PaymentModel) SetStatus(status PaymentStatus, at time.Time) {
+	p.Status = status
+	var field **time.Time
+	switch status {
+	case PaymentStatusAuthorized:
+		field = &p.AuthorizedAt
+	case PaymentStatusCaptured:
+		field = &p.CapturedAt
+	case PaymentStatusFailed:
+		field = &p.FailedAt
+	case PaymentStatusPartiallyRefunded:
+		field = &p.PartiallyRefundedAt
+	case PaymentStatusRefunded:
+		field = &p.RefundedAt
+	default:
+		return
+	}
+	if *field == nil {
+		*field = &at
+	}
+}
+
+// PaymentFilter 描述支付列表查询的过滤条件，空值表示不过滤
+type PaymentFilter struct {
+	UserID        string
+	Status        PaymentStatus
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
 }
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// InboxMessage 记录已处理完毕的消息，用于识别重复投递的消息。
+// MessageID 为上游发件箱事件的ID，随消息的 MessageId 投递，与处理结果在同一事务中落库
+type InboxMessage struct {
+	MessageID   string    `gorm:"type:varchar(64);primaryKey;comment:消息ID"`
+	RoutingKey  string    `gorm:"type:varchar(64);not null;comment:路由键"`
+	ProcessedAt time.Time `gorm:"index:idx_processed;comment:处理时间"`
+}
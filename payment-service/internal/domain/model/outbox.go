@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// OutboxEvent 是写入发件箱的领域事件，与支付变更在同一事务中落库，
+// 由 relay 异步投递到 RabbitMQ 后标记为已发送
+type OutboxEvent struct {
+	ID         string     `gorm:"type:varchar(64);primaryKey;comment:事件ID"`
+	RoutingKey string     `gorm:"type:varchar(64);not null;comment:路由键"`
+	Payload    string     `gorm:"type:text;not null;comment:事件内容"`
+	CreatedAt  time.Time  `gorm:"index:idx_created;comment:创建时间"`
+	SentAt     *time.Time `gorm:"index:idx_sent;comment:发送时间"`
+
+	// ClaimedBy 与 ClaimedUntil 为领取该事件投递的 relay 副本及领取期限，期限内其他副本不会投递该事件
+	ClaimedBy    string     `gorm:"type:varchar(128);not null;default:'';comment:领取者"`
+	ClaimedUntil *time.Time `gorm:"comment:领取期限"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RefundStatus string
+
+const (
+	// RefundStatusPending 退款已记录并占用可退款金额，等待支付渠道确认
+	RefundStatusPending RefundStatus = "pending"
+	// RefundStatusSucceeded 支付渠道已退款，金额已计入支付的累计退款金额
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	// RefundStatusFailed 支付渠道拒绝了退款，占用的金额已释放
+	RefundStatusFailed RefundStatus = "failed"
+)
+
+// RefundModel 是支付的一次退款，同一支付可以多次部分退款，累计金额不超过支付金额。
+// 管理端退款先写入 pending 记录再调用支付渠道，RefundID 作为渠道侧的退款流水号，重试时不会重复退款
+type RefundModel struct {
+	ID        uint      `gorm:"primaryKey"`
+	RefundID  uuid.UUID `gorm:"type:varchar(128);not null;uniqueIndex:idx_refund_id;comment:退款ID"`
+	PaymentID uuid.UUID `gorm:"type:varchar(128);not null;index:idx_payment_id;index:idx_payment_idempotency_key,priority:1;comment:支付ID"`
+	Amount    int64     `gorm:"type:bigint;not null;comment:退款金额"`
+	Reason    string    `gorm:"type:varchar(255);not null;default:'';comment:退款原因"`
+	// Status 引入两阶段退款前的记录均为已完成的退款
+	Status RefundStatus `gorm:"type:varchar(16);not null;default:'succeeded';comment:退款状态"`
+	// IdempotencyKey 客户端传入的幂等键，同一支付下相同的键只会退款一次
+	IdempotencyKey string    `gorm:"type:varchar(128);not null;default:'';index:idx_payment_idempotency_key,priority:2;comment:幂等键"`
+	CreatedAt      time.Time `gorm:"comment:退款时间"`
+}
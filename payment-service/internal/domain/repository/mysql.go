@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"order-microsystem/outbox"
 	"order-microsystem/payment-service/internal/domain/model"
+	"time"
 )
 
 type MySQLRepository struct {
@@ -16,12 +21,96 @@ func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
 }
 
 func (r *MySQLRepository) AutoMigration() error {
-	if err := r.db.AutoMigrate(&model.PaymentModel{}); err != nil {
+	if err := r.checkDuplicatePayments(); err != nil {
+		return err
+	}
+	if err := r.addLegacyStatusColumn(); err != nil {
+		return err
+	}
+	if err := r.db.AutoMigrate(&model.PaymentModel{}, &model.RefundModel{}, &model.OutboxEvent{}, &model.InboxMessage{}); err != nil {
 		return fmt.Errorf("failed to autoMigrate Product model: %v", err)
 	}
+	// 引入退款记录前已退款的支付均为全额退款
+	if err := r.db.Model(&model.PaymentModel{}).
+		Where("refunded_at IS NOT NULL AND refunded_amount = 0").
+		Update("refunded_amount", gorm.Expr("total_price")).Error; err != nil {
+		return fmt.Errorf("failed to backfill refunded amount: %v", err)
+	}
+	if err := r.backfillStatuses(); err != nil {
+		return fmt.Errorf("failed to backfill payment status: %v", err)
+	}
 	return nil
 }
 
+// checkDuplicatePayments 在为 order_id 建立唯一索引前确认没有订单存在多条支付记录。
+// 重复的支付意味着订单被重复扣款，需要人工退款并删除多余的记录后才能完成迁移
+func (r *MySQLRepository) checkDuplicatePayments() error {
+	if !r.db.Migrator().HasTable(&model.PaymentModel{}) {
+		return nil
+	}
+	var orderIDs []string
+	if err := r.db.Model(&model.PaymentModel{}).
+		Group("order_id").
+		Having("COUNT(*) > 1").
+		Limit(10).
+		Pluck("order_id", &orderIDs).Error; err != nil {
+		return fmt.Errorf("failed to check duplicate payments: %v", err)
+	}
+	if len(orderIDs) > 0 {
+		return fmt.Errorf("orders with duplicate payments must be resolved before migration: %v", orderIDs)
+	}
+	return nil
+}
+
+// addLegacyStatusColumn 为引入支付状态前的支付表添加默认值为 completed 的 status 列。
+// 引入支付状态前只有扣款成功才会写入支付记录，若直接以 pending 为默认值添加该列，这些记录将无法与尚未授权的支付区分。
+// 列添加后由 AutoMigrate 将默认值改为 pending，已有记录保持 completed，再由 backfillStatuses 换算
+func (r *MySQLRepository) addLegacyStatusColumn() error {
+	migrator := r.db.Migrator()
+	if !migrator.HasTable(&model.PaymentModel{}) || migrator.HasColumn(&model.PaymentModel{}, "Status") {
+		return nil
+	}
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(&model.PaymentModel{}); err != nil {
+		return fmt.Errorf("failed to parse payment model: %v", err)
+	}
+	if err := r.db.Exec("ALTER TABLE ? ADD COLUMN status varchar(32) NOT NULL DEFAULT 'completed'",
+		clause.Table{Name: stmt.Schema.Table}).Error; err != nil {
+		return fmt.Errorf("failed to add payment status column: %v", err)
+	}
+	return nil
+}
+
+// backfillStatuses 将引入支付状态流转前的 completed 状态换算为 captured、partially_refunded 或 refunded，
+// 并以记录的创建、更新时间补齐各状态的时间
+func (r *MySQLRepository) backfillStatuses() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		legacy := tx.Model(&model.PaymentModel{}).Where("status = ?", "completed")
+		if err := legacy.Session(&gorm.Session{}).Where("refunded_at IS NOT NULL").Updates(map[string]interface{}{
+			"status":      model.PaymentStatusRefunded,
+			"captured_at": gorm.Expr("created_at"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := legacy.Session(&gorm.Session{}).Where("refunded_amount > 0").Updates(map[string]interface{}{
+			"status":                model.PaymentStatusPartiallyRefunded,
+			"captured_at":           gorm.Expr("created_at"),
+			"partially_refunded_at": gorm.Expr("updated_at"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := legacy.Session(&gorm.Session{}).Updates(map[string]interface{}{
+			"status":      model.PaymentStatusCaptured,
+			"captured_at": gorm.Expr("created_at"),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.PaymentModel{}).
+			Where("status = ? AND failed_at IS NULL", model.PaymentStatusFailed).
+			Update("failed_at", gorm.Expr("created_at")).Error
+	})
+}
+
 func (r *MySQLRepository) CreatePayment(paymentModel *model.PaymentModel) error {
 	if err := r.db.Create(paymentModel).Error; err != nil {
 		return fmt.Errorf("failed to create payment: %v", err)
@@ -29,20 +118,325 @@ func (r *MySQLRepository) CreatePayment(paymentModel *model.PaymentModel) error
 	return nil
 }
 
-func (r *MySQLRepository) GetPayment(user_id string) (*model.PaymentModel, error) {
+// GetOrCreatePayment 写入订单的支付记录，订单已有支付记录时不写入并返回已有的记录。
+// order_id 上的唯一索引保证同一订单的消息被并发处理时也只会有一条支付记录
+func (r *MySQLRepository) GetOrCreatePayment(payment *model.PaymentModel) (*model.PaymentModel, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(payment)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create payment: %v", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return payment, nil
+	}
+
+	existing, err := r.GetPaymentByOrderID(payment.OrderID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment: %v", err)
+	}
+	return existing, nil
+}
+
+// ListPayments 按创建时间倒序返回满足 filter 的支付记录，beforeID 大于 0 时只返回ID小于 beforeID 的记录，用于翻页
+func (r *MySQLRepository) ListPayments(filter model.PaymentFilter, beforeID uint, limit int) ([]*model.PaymentModel, error) {
+	query := r.db.Model(&model.PaymentModel{})
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var payments []*model.PaymentModel
+	if err := query.Order("id DESC").Limit(limit).Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *MySQLRepository) GetPaymentByOrderID(orderID string) (*model.PaymentModel, error) {
+	var payment model.PaymentModel
+	if err := r.db.Where("order_id = ?", orderID).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetPaymentByPaymentID 按支付ID查询支付记录
+func (r *MySQLRepository) GetPaymentByPaymentID(paymentID string) (*model.PaymentModel, error) {
+	var payment model.PaymentModel
+	if err := r.db.Where("payment_id = ?", paymentID).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// UpdatePaymentStatus 仅当支付仍处于 from 状态时写入 payment 的状态及其他非零字段，返回是否更新成功。
+// events 与状态变更在同一事务中写入发件箱
+func (r *MySQLRepository) UpdatePaymentStatus(payment *model.PaymentModel, from model.PaymentStatus, events ...*model.OutboxEvent) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(payment).Where("status = ?", from).Updates(payment)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		for _, event := range events {
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+		}
+		updated = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update payment status: %v", err)
+	}
+	return updated, nil
+}
+
+var (
+	// ErrRefundExceedsPayment 累计退款金额将超过支付金额
+	ErrRefundExceedsPayment = errors.New("refund exceeds payment amount")
+	// ErrPaymentNotRefundable 支付尚未扣款或已全额退款
+	ErrPaymentNotRefundable = errors.New("payment is not refundable")
+)
+
+// AddRefund 锁定支付记录后写入已完成的退款记录并累加支付的累计退款金额，将支付置为 partially_refunded 或 refunded。
+// newEvent 以退款前的支付记录构建与退款在同一事务中写入发件箱的事件，为 nil 时不写入事件。
+// 返回退款后的支付记录，支付不可退款时返回 ErrPaymentNotRefundable，累计退款金额将超过支付金额时返回 ErrRefundExceedsPayment
+func (r *MySQLRepository) AddRefund(paymentID uint, refund *model.RefundModel, newEvent func(payment *model.PaymentModel) (*model.OutboxEvent, error)) (*model.PaymentModel, error) {
 	var payment model.PaymentModel
-	if err := r.db.Where("user_id = ?", user_id).First(&payment).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, err
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, paymentID).Error; err != nil {
+			return err
+		}
+		if !payment.Status.Refundable() {
+			return ErrPaymentNotRefundable
 		}
+		refund.Status = model.RefundStatusSucceeded
+		if err := tx.Create(refund).Error; err != nil {
+			return err
+		}
+		return applyRefund(tx, &payment, refund, newEvent)
+	})
+	if err != nil {
+		return nil, refundError(err)
 	}
 	return &payment, nil
 }
 
-func (r *MySQLRepository) GetAllPayment(user_id string) ([]*model.PaymentModel, error) {
-	var payments []*model.PaymentModel
-	if err := r.db.Where("user_id = ?", user_id).Find(&payments).Error; err != nil {
+// CreatePendingRefund 锁定支付记录后写入 pending 的退款记录，处理中的退款金额与累计退款金额一同不能超过支付金额。
+// refund.IdempotencyKey 不为空且该支付已有相同幂等键的退款时不写入，返回已有的退款记录。
+// 支付不可退款时返回 ErrPaymentNotRefundable，金额超过可退款金额时返回 ErrRefundExceedsPayment
+func (r *MySQLRepository) CreatePendingRefund(paymentID uint, refund *model.RefundModel) (*model.RefundModel, error) {
+	result := refund
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var payment model.PaymentModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, paymentID).Error; err != nil {
+			return err
+		}
+		if refund.IdempotencyKey != "" {
+			var existing []*model.RefundModel
+			if err := tx.Where("payment_id = ? AND idempotency_key = ?", payment.PaymentID, refund.IdempotencyKey).
+				Limit(1).
+				Find(&existing).Error; err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				result = existing[0]
+				return nil
+			}
+		}
+		if !payment.Status.Refundable() {
+			return ErrPaymentNotRefundable
+		}
+		var pending int64
+		if err := tx.Model(&model.RefundModel{}).
+			Where("payment_id = ? AND status = ?", payment.PaymentID, model.RefundStatusPending).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&pending).Error; err != nil {
+			return err
+		}
+		if payment.RefundedAmount+pending+refund.Amount > payment.TotalPrice {
+			return ErrRefundExceedsPayment
+		}
+		refund.Status = model.RefundStatusPending
+		return tx.Create(refund).Error
+	})
+	if err != nil {
+		return nil, refundError(err)
+	}
+	return result, nil
+}
+
+// ListPendingRefunds 按创建顺序返回支付中尚未完成的退款
+func (r *MySQLRepository) ListPendingRefunds(paymentID uuid.UUID) ([]*model.RefundModel, error) {
+	var refunds []*model.RefundModel
+	err := r.db.Where("payment_id = ? AND status = ?", paymentID, model.RefundStatusPending).
+		Order("id").
+		Find(&refunds).Error
+	return refunds, err
+}
+
+// CompleteRefund 在支付渠道确认退款后将 pending 的退款置为 succeeded，并像 AddRefund 一样累加支付的累计退款金额。
+// 退款已完成或已失败时不做修改，返回其当前状态。返回退款后的支付记录与退款记录
+func (r *MySQLRepository) CompleteRefund(paymentID uint, refundID uint, newEvent func(payment *model.PaymentModel) (*model.OutboxEvent, error)) (*model.PaymentModel, *model.RefundModel, error) {
+	var payment model.PaymentModel
+	var refund model.RefundModel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, paymentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&refund, refundID).Error; err != nil {
+			return err
+		}
+		if refund.Status != model.RefundStatusPending {
+			return nil
+		}
+		if err := tx.Model(&refund).Update("status", model.RefundStatusSucceeded).Error; err != nil {
+			return err
+		}
+		return applyRefund(tx, &payment, &refund, newEvent)
+	})
+	if err != nil {
+		return nil, nil, refundError(err)
+	}
+	return &payment, &refund, nil
+}
+
+// FailRefund 将支付渠道拒绝的 pending 退款置为 failed，释放其占用的可退款金额
+func (r *MySQLRepository) FailRefund(refundID uint) error {
+	return r.db.Model(&model.RefundModel{}).
+		Where("id = ? AND status = ?", refundID, model.RefundStatusPending).
+		Update("status", model.RefundStatusFailed).Error
+}
+
+// applyRefund 将退款金额累加到已锁定的支付记录，并写入 newEvent 构建的事件
+func applyRefund(tx *gorm.DB, payment *model.PaymentModel, refund *model.RefundModel, newEvent func(payment *model.PaymentModel) (*model.OutboxEvent, error)) error {
+	refundedAmount := payment.RefundedAmount + refund.Amount
+	if refundedAmount > payment.TotalPrice {
+		return ErrRefundExceedsPayment
+	}
+
+	if newEvent != nil {
+		event, err := newEvent(payment)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+	}
+
+	payment.RefundedAmount = refundedAmount
+	if refundedAmount == payment.TotalPrice {
+		payment.SetStatus(model.PaymentStatusRefunded, time.Now())
+	} else {
+		payment.SetStatus(model.PaymentStatusPartiallyRefunded, time.Now())
+	}
+	return tx.Model(payment).Updates(map[string]interface{}{
+		"refunded_amount":       payment.RefundedAmount,
+		"status":                payment.Status,
+		"partially_refunded_at": payment.PartiallyRefundedAt,
+		"refunded_at":           payment.RefundedAt,
+	}).Error
+}
+
+// refundError 原样返回调用方需要区分的退款错误，其余错误附加上下文
+func refundError(err error) error {
+	if errors.Is(err, ErrRefundExceedsPayment) || errors.Is(err, ErrPaymentNotRefundable) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return fmt.Errorf("failed to add refund: %v", err)
+}
+
+// Transaction 在一个数据库事务中执行 fn，fn 中通过 txRepo 进行的读写要么全部提交，要么全部回滚
+func (r *MySQLRepository) Transaction(fn func(txRepo *MySQLRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&MySQLRepository{db: tx})
+	})
+}
+
+// AddOutboxEvent 将领域事件写入发件箱，应与对应的支付变更处于同一事务中
+func (r *MySQLRepository) AddOutboxEvent(event *model.OutboxEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to add outbox event: %v", err)
+	}
+	return nil
+}
+
+// IsMessageProcessed 判断消息是否已处理完毕
+func (r *MySQLRepository) IsMessageProcessed(messageID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&model.InboxMessage{}).Where("message_id = ?", messageID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to query inbox: %v", err)
+	}
+	return count > 0, nil
+}
+
+// MarkMessageProcessed 记录消息已处理完毕，应与消息的处理结果处于同一事务中；重复记录同一消息不会报错
+func (r *MySQLRepository) MarkMessageProcessed(message *model.InboxMessage) error {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(message).Error; err != nil {
+		return fmt.Errorf("failed to mark message processed: %v", err)
+	}
+	return nil
+}
+
+// ClaimPending 按写入顺序领取尚未发送的发件箱事件。以 SKIP LOCKED 跳过其他副本正在领取的行，
+// 领取后的 lease 内其他副本不会再领取这些事件
+func (r *MySQLRepository) ClaimPending(ctx context.Context, owner string, lease time.Duration, limit int) ([]*outbox.Event, error) {
+	var events []*model.OutboxEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND (claimed_by = ? OR claimed_until IS NULL OR claimed_until < ?)", owner, now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		return tx.Model(&model.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{"claimed_by": owner, "claimed_until": now.Add(lease)}).Error
+	})
+	if err != nil {
 		return nil, err
 	}
-	return payments, nil
+
+	claimed := make([]*outbox.Event, 0, len(events))
+	for _, event := range events {
+		claimed = append(claimed, &outbox.Event{
+			ID:         event.ID,
+			RoutingKey: event.RoutingKey,
+			Payload:    event.Payload,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return claimed, nil
+}
+
+// MarkSent 将发件箱事件标记为已发送
+func (r *MySQLRepository) MarkSent(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Update("sent_at", time.Now()).Error
 }
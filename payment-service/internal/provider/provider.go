@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-microsystem/payment-service/pkg/config"
+)
+
+const (
+	// ProviderSimulator 本地模拟支付渠道，不产生真实扣款
+	ProviderSimulator = "simulator"
+)
+
+// Status 渠道交易的状态
+type Status string
+
+const (
+	// StatusAuthorized 已授权，金额已冻结但尚未扣款
+	StatusAuthorized Status = "authorized"
+	// StatusCaptured 已扣款
+	StatusCaptured Status = "captured"
+	// StatusDeclined 渠道拒绝了本次操作
+	StatusDeclined Status = "declined"
+	// StatusRefunded 已扣款的金额已全部退回
+	StatusRefunded Status = "refunded"
+)
+
+var (
+	// ErrTimeout 渠道未在超时时间内响应，操作结果未知，调用方应使用 Query 确认或以相同的流水号重试
+	ErrTimeout = errors.New("payment provider timed out")
+	// ErrNotFound 渠道中不存在该流水号的交易
+	ErrNotFound = errors.New("payment transaction not found")
+	// ErrInvalidState 交易当前的状态不允许该操作，如未授权即扣款或退款金额超过已扣款金额
+	ErrInvalidState = errors.New("invalid payment transaction state")
+)
+
+// AuthorizeRequest 授权请求，Reference 为商户侧流水号，同一流水号重复授权时返回已有的交易
+type AuthorizeRequest struct {
+	Reference  string
+	CustomerID string
+	Amount     int64
+}
+
+// RefundRequest 退款请求，RefundReference 为商户侧退款流水号，同一退款流水号重复退款时不会重复退回
+type RefundRequest struct {
+	Reference       string
+	RefundReference string
+	Amount          int64
+}
+
+// Transaction 渠道侧交易的当前状态
+type Transaction struct {
+	Reference     string
+	TransactionID string
+	Status        Status
+	// Amount 授权金额
+	Amount         int64
+	CapturedAmount int64
+	RefundedAmount int64
+	// DeclineReason 渠道拒绝的原因，仅在 Status 为 declined 时有值
+	DeclineReason string
+}
+
+// Declined 返回渠道是否拒绝了本次操作
+func (t *Transaction) Declined() bool {
+	return t.Status == StatusDeclined
+}
+
+// PaymentProvider 支付渠道。所有操作都以商户侧流水号定位交易，并且对同一流水号(退款为退款流水号)幂等，
+// 调用方在超时或进程重启后可以安全地重试。被渠道拒绝的操作返回 Status 为 declined 的交易而不是 error
+type PaymentProvider interface {
+	// Name 渠道名称，记录在支付记录中
+	Name() string
+	// Authorize 冻结客户的支付金额
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error)
+	// Capture 对已授权的交易扣款，amount 不能超过授权金额
+	Capture(ctx context.Context, reference string, amount int64) (*Transaction, error)
+	// Refund 退回已扣款的部分或全部金额，累计退款金额不能超过已扣款金额
+	Refund(ctx context.Context, req RefundRequest) (*Transaction, error)
+	// Query 查询交易的当前状态
+	Query(ctx context.Context, reference string) (*Transaction, error)
+}
+
+// NewProvider 按配置创建支付渠道，名称为空时使用 simulator
+func NewProvider(cfg *config.PaymentConfig) (PaymentProvider, error) {
+	switch cfg.Provider {
+	case ProviderSimulator, "":
+		return NewSimulator(cfg.Simulator), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider: %s", cfg.Provider)
+	}
+}
@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"order-microsystem/payment-service/pkg/config"
+	"sync"
+	"time"
+)
+
+// Operation 渠道操作，用于为模拟渠道编排各操作的结果
+type Operation string
+
+const (
+	OpAuthorize Operation = "authorize"
+	OpCapture   Operation = "capture"
+	OpRefund    Operation = "refund"
+	OpQuery     Operation = "query"
+)
+
+// Behavior 模拟渠道对一次调用的处理方式
+type Behavior string
+
+const (
+	// BehaviorSuccess 正常处理
+	BehaviorSuccess Behavior = "success"
+	// BehaviorDecline 拒绝本次操作，对查询无效
+	BehaviorDecline Behavior = "decline"
+	// BehaviorTimeout 操作在渠道侧生效，但响应丢失，调用方在等待超时后收到 ErrTimeout
+	BehaviorTimeout Behavior = "timeout"
+)
+
+// Outcome 一次调用的编排结果，Latency 为 0 时使用配置的延迟
+type Outcome struct {
+	Behavior Behavior
+	Latency  time.Duration
+	// Reason 拒绝原因，为空时使用 declined_by_simulator
+	Reason string
+}
+
+// Call 模拟渠道收到的一次调用
+type Call struct {
+	Operation Operation
+	Reference string
+	Amount    int64
+}
+
+type simulatedTransaction struct {
+	Transaction
+	// refunds 已处理的退款，按退款流水号索引
+	refunds map[string]int64
+}
+
+// Simulator 本地模拟支付渠道，交易只保存在内存中，进程重启后丢失。
+// 未编排结果的调用按配置处理：等待 Latency 后成功，授权金额超过 DeclineAbove 时拒绝。
+// 测试中可以通过 Script 按调用顺序编排各操作的成功、拒绝、超时和延迟，结果是确定的
+type Simulator struct {
+	cfg config.SimulatorConfig
+
+	mu           sync.Mutex
+	scripts      map[Operation][]Outcome
+	transactions map[string]*simulatedTransaction
+	calls        []Call
+}
+
+func NewSimulator(cfg config.SimulatorConfig) *Simulator {
+	return &Simulator{
+		cfg:          cfg,
+		scripts:      make(map[Operation][]Outcome),
+		transactions: make(map[string]*simulatedTransaction),
+	}
+}
+
+func (s *Simulator) Name() string {
+	return ProviderSimulator
+}
+
+// Script 为 op 追加编排结果，之后的调用依次使用这些结果，用完后恢复按配置处理
+func (s *Simulator) Script(op Operation, outcomes ...Outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[op] = append(s.scripts[op], outcomes...)
+}
+
+// Calls 按顺序返回模拟渠道收到的全部调用
+func (s *Simulator) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, len(s.calls))
+	copy(calls, s.calls)
+	return calls
+}
+
+// Reset 清空编排结果、交易和调用记录
+func (s *Simulator) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts = make(map[Operation][]Outcome)
+	s.transactions = make(map[string]*simulatedTransaction)
+	s.calls = nil
+}
+
+func (s *Simulator) Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error) {
+	return s.do(ctx, OpAuthorize, req.Reference, req.Amount, func(outcome Outcome) (*Transaction, error) {
+		if tx, ok := s.transactions[req.Reference]; ok {
+			return &tx.Transaction, nil
+		}
+		if req.Amount <= 0 {
+			return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidState)
+		}
+
+		tx := &simulatedTransaction{
+			Transaction: Transaction{
+				Reference:     req.Reference,
+				TransactionID: "sim_" + uuid.New().String(),
+				Status:        StatusAuthorized,
+				Amount:        req.Amount,
+			},
+			refunds: make(map[string]int64),
+		}
+		switch {
+		case outcome.Behavior == BehaviorDecline:
+			tx.Status = StatusDeclined
+			tx.DeclineReason = declineReason(outcome)
+		case s.cfg.DeclineAbove > 0 && req.Amount > s.cfg.DeclineAbove:
+			tx.Status = StatusDeclined
+			tx.DeclineReason = "amount_limit_exceeded"
+		}
+		s.transactions[req.Reference] = tx
+		return &tx.Transaction, nil
+	})
+}
+
+func (s *Simulator) Capture(ctx context.Context, reference string, amount int64) (*Transaction, error) {
+	return s.do(ctx, OpCapture, reference, amount, func(outcome Outcome) (*Transaction, error) {
+		tx, ok := s.transactions[reference]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		// 重复扣款相同金额时返回已扣款的交易
+		if (tx.Status == StatusCaptured || tx.Status == StatusRefunded) && tx.CapturedAmount == amount {
+			return &tx.Transaction, nil
+		}
+		if tx.Status != StatusAuthorized {
+			return nil, fmt.Errorf("%w: cannot capture a %s transaction", ErrInvalidState, tx.Status)
+		}
+		if amount <= 0 || amount > tx.Amount {
+			return nil, fmt.Errorf("%w: capture amount %d exceeds authorized amount %d", ErrInvalidState, amount, tx.Amount)
+		}
+		if outcome.Behavior == BehaviorDecline {
+			return declined(tx, outcome), nil
+		}
+
+		tx.Status = StatusCaptured
+		tx.CapturedAmount = amount
+		return &tx.Transaction, nil
+	})
+}
+
+func (s *Simulator) Refund(ctx context.Context, req RefundRequest) (*Transaction, error) {
+	return s.do(ctx, OpRefund, req.Reference, req.Amount, func(outcome Outcome) (*Transaction, error) {
+		tx, ok := s.transactions[req.Reference]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		if _, ok := tx.refunds[req.RefundReference]; ok {
+			return &tx.Transaction, nil
+		}
+		if tx.Status != StatusCaptured {
+			return nil, fmt.Errorf("%w: cannot refund a %s transaction", ErrInvalidState, tx.Status)
+		}
+		if req.Amount <= 0 || tx.RefundedAmount+req.Amount > tx.CapturedAmount {
+			return nil, fmt.Errorf("%w: refund amount %d exceeds refundable amount %d",
+				ErrInvalidState, req.Amount, tx.CapturedAmount-tx.RefundedAmount)
+		}
+		if outcome.Behavior == BehaviorDecline {
+			return declined(tx, outcome), nil
+		}
+
+		tx.refunds[req.RefundReference] = req.Amount
+		tx.RefundedAmount += req.Amount
+		if tx.RefundedAmount == tx.CapturedAmount {
+			tx.Status = StatusRefunded
+		}
+		return &tx.Transaction, nil
+	})
+}
+
+func (s *Simulator) Query(ctx context.Context, reference string) (*Transaction, error) {
+	return s.do(ctx, OpQuery, reference, 0, func(Outcome) (*Transaction, error) {
+		tx, ok := s.transactions[reference]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return &tx.Transaction, nil
+	})
+}
+
+// do 取出 op 的下一个编排结果，模拟延迟后在锁内执行 apply，返回交易的副本。
+// 超时的调用在 apply 生效后等待配置的超时时间或 ctx 结束，再返回 ErrTimeout
+func (s *Simulator) do(ctx context.Context, op Operation, reference string, amount int64, apply func(outcome Outcome) (*Transaction, error)) (*Transaction, error) {
+	s.mu.Lock()
+	outcome := Outcome{Behavior: BehaviorSuccess}
+	if script := s.scripts[op]; len(script) > 0 {
+		outcome = script[0]
+		s.scripts[op] = script[1:]
+	}
+	s.calls = append(s.calls, Call{Operation: op, Reference: reference, Amount: amount})
+	s.mu.Unlock()
+
+	latency := outcome.Latency
+	if latency == 0 {
+		latency = s.cfg.Latency
+	}
+	if err := sleep(ctx, latency); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	tx, err := apply(outcome)
+	var result *Transaction
+	if tx != nil {
+		copied := *tx
+		result = &copied
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if outcome.Behavior == BehaviorTimeout {
+		sleep(ctx, s.cfg.Timeout)
+		return nil, ErrTimeout
+	}
+	return result, nil
+}
+
+// declined 返回被拒绝操作的结果，交易本身的状态保持不变
+func declined(tx *simulatedTransaction, outcome Outcome) *Transaction {
+	result := tx.Transaction
+	result.Status = StatusDeclined
+	result.DeclineReason = declineReason(outcome)
+	return &result
+}
+
+func declineReason(outcome Outcome) string {
+	if outcome.Reason != "" {
+		return outcome.Reason
+	}
+	return "declined_by_simulator"
+}
+
+// sleep 等待 d 或 ctx 结束，ctx 先结束时返回 ctx 的错误
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"order-microsystem/payment-service/pkg/config"
+	"testing"
+)
+
+func TestSimulatorAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.SimulatorConfig
+		script     []Outcome
+		amount     int64
+		wantErr    error
+		wantStatus Status
+		wantReason string
+	}{
+		{name: "success", amount: 100, wantStatus: StatusAuthorized},
+		{name: "scripted decline", script: []Outcome{{Behavior: BehaviorDecline, Reason: "insufficient_funds"}}, amount: 100,
+			wantStatus: StatusDeclined, wantReason: "insufficient_funds"},
+		{name: "decline without reason", script: []Outcome{{Behavior: BehaviorDecline}}, amount: 100,
+			wantStatus: StatusDeclined, wantReason: "declined_by_simulator"},
+		{name: "amount above limit", cfg: config.SimulatorConfig{DeclineAbove: 50}, amount: 100,
+			wantStatus: StatusDeclined, wantReason: "amount_limit_exceeded"},
+		{name: "amount at limit", cfg: config.SimulatorConfig{DeclineAbove: 100}, amount: 100, wantStatus: StatusAuthorized},
+		{name: "invalid amount", amount: 0, wantErr: ErrInvalidState},
+		{name: "timeout", script: []Outcome{{Behavior: BehaviorTimeout}}, amount: 100, wantErr: ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulator(tt.cfg)
+			sim.Script(OpAuthorize, tt.script...)
+
+			tx, err := sim.Authorize(context.Background(), AuthorizeRequest{Reference: "order-1", CustomerID: "customer-1", Amount: tt.amount})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tx.Status != tt.wantStatus || tx.DeclineReason != tt.wantReason {
+				t.Errorf("Authorize() = %s %q, want %s %q", tx.Status, tx.DeclineReason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSimulatorCapture(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorize  bool
+		script     []Outcome
+		amount     int64
+		wantErr    error
+		wantStatus Status
+	}{
+		{name: "success", authorize: true, amount: 100, wantStatus: StatusCaptured},
+		{name: "declined", authorize: true, script: []Outcome{{Behavior: BehaviorDecline}}, amount: 100, wantStatus: StatusDeclined},
+		{name: "exceeds authorized amount", authorize: true, amount: 101, wantErr: ErrInvalidState},
+		{name: "unknown reference", amount: 100, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulator(config.SimulatorConfig{})
+			if tt.authorize {
+				if _, err := sim.Authorize(context.Background(), AuthorizeRequest{Reference: "order-1", Amount: 100}); err != nil {
+					t.Fatalf("Authorize() error = %v", err)
+				}
+			}
+			sim.Script(OpCapture, tt.script...)
+
+			tx, err := sim.Capture(context.Background(), "order-1", tt.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Capture() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tx.Status != tt.wantStatus {
+				t.Errorf("Capture() status = %s, want %s", tx.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestSimulatorTimeoutThenRetry 超时的调用在渠道侧已生效，以相同的流水号重试时返回已有结果而不会重复处理
+func TestSimulatorTimeoutThenRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Operation
+		call func(sim *Simulator) (*Transaction, error)
+		want Transaction
+	}{
+		{
+			name: "authorize",
+			op:   OpAuthorize,
+			call: func(sim *Simulator) (*Transaction, error) {
+				return sim.Authorize(context.Background(), AuthorizeRequest{Reference: "order-1", Amount: 100})
+			},
+			want: Transaction{Status: StatusAuthorized, Amount: 100},
+		},
+		{
+			name: "capture",
+			op:   OpCapture,
+			call: func(sim *Simulator) (*Transaction, error) {
+				return sim.Capture(context.Background(), "order-1", 100)
+			},
+			want: Transaction{Status: StatusCaptured, Amount: 100, CapturedAmount: 100},
+		},
+		{
+			name: "refund",
+			op:   OpRefund,
+			call: func(sim *Simulator) (*Transaction, error) {
+				return sim.Refund(context.Background(), RefundRequest{Reference: "order-1", RefundReference: "refund-1", Amount: 40})
+			},
+			want: Transaction{Status: StatusCaptured, Amount: 100, CapturedAmount: 100, RefundedAmount: 40},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulator(config.SimulatorConfig{})
+			// 先完成被测操作之前的步骤
+			if tt.op != OpAuthorize {
+				if _, err := sim.Authorize(context.Background(), AuthorizeRequest{Reference: "order-1", Amount: 100}); err != nil {
+					t.Fatalf("Authorize() error = %v", err)
+				}
+			}
+			if tt.op == OpRefund {
+				if _, err := sim.Capture(context.Background(), "order-1", 100); err != nil {
+					t.Fatalf("Capture() error = %v", err)
+				}
+			}
+
+			sim.Script(tt.op, Outcome{Behavior: BehaviorTimeout})
+			if _, err := tt.call(sim); !errors.Is(err, ErrTimeout) {
+				t.Fatalf("first call error = %v, want %v", err, ErrTimeout)
+			}
+			tx, err := tt.call(sim)
+			if err != nil {
+				t.Fatalf("retry error = %v", err)
+			}
+			if tx.Status != tt.want.Status || tx.Amount != tt.want.Amount ||
+				tx.CapturedAmount != tt.want.CapturedAmount || tx.RefundedAmount != tt.want.RefundedAmount {
+				t.Errorf("retry = %+v, want %+v", *tx, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulatorRefundLimits(t *testing.T) {
+	type step struct {
+		reference    string
+		amount       int64
+		script       []Outcome
+		wantErr      error
+		wantStatus   Status
+		wantRefunded int64
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "partial then full",
+			steps: []step{
+				{reference: "refund-1", amount: 30, wantStatus: StatusCaptured, wantRefunded: 30},
+				{reference: "refund-2", amount: 70, wantStatus: StatusRefunded, wantRefunded: 100},
+			},
+		},
+		{
+			name: "exceeds refundable amount",
+			steps: []step{
+				{reference: "refund-1", amount: 60, wantStatus: StatusCaptured, wantRefunded: 60},
+				{reference: "refund-2", amount: 50, wantErr: ErrInvalidState},
+				{reference: "refund-3", amount: 40, wantStatus: StatusRefunded, wantRefunded: 100},
+			},
+		},
+		{
+			name: "duplicate refund reference",
+			steps: []step{
+				{reference: "refund-1", amount: 60, wantStatus: StatusCaptured, wantRefunded: 60},
+				{reference: "refund-1", amount: 60, wantStatus: StatusCaptured, wantRefunded: 60},
+			},
+		},
+		{
+			name: "declined refund is not applied",
+			steps: []step{
+				{reference: "refund-1", amount: 60, script: []Outcome{{Behavior: BehaviorDecline}}, wantStatus: StatusDeclined},
+				{reference: "refund-2", amount: 100, wantStatus: StatusRefunded, wantRefunded: 100},
+			},
+		},
+		{
+			name: "refund after fully refunded",
+			steps: []step{
+				{reference: "refund-1", amount: 100, wantStatus: StatusRefunded, wantRefunded: 100},
+				{reference: "refund-2", amount: 1, wantErr: ErrInvalidState},
+			},
+		},
+		{
+			name: "invalid amount",
+			steps: []step{
+				{reference: "refund-1", amount: 0, wantErr: ErrInvalidState},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulator(config.SimulatorConfig{})
+			if _, err := sim.Authorize(context.Background(), AuthorizeRequest{Reference: "order-1", Amount: 100}); err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+			if _, err := sim.Capture(context.Background(), "order-1", 100); err != nil {
+				t.Fatalf("Capture() error = %v", err)
+			}
+
+			for i, step := range tt.steps {
+				sim.Script(OpRefund, step.script...)
+				tx, err := sim.Refund(context.Background(), RefundRequest{Reference: "order-1", RefundReference: step.reference, Amount: step.amount})
+				if !errors.Is(err, step.wantErr) {
+					t.Fatalf("step %d: Refund() error = %v, want %v", i, err, step.wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if tx.Status != step.wantStatus || tx.RefundedAmount != step.wantRefunded {
+					t.Errorf("step %d: Refund() = %s refunded %d, want %s refunded %d",
+						i, tx.Status, tx.RefundedAmount, step.wantStatus, step.wantRefunded)
+				}
+			}
+		})
+	}
+}
+
+// TestSimulatorReset 模拟渠道重启后交易丢失，之后对原流水号的操作返回 ErrNotFound
+func TestSimulatorReset(t *testing.T) {
+	sim := NewSimulator(config.SimulatorConfig{})
+	if _, err := sim.Authorize(context.Background(), AuthorizeRequest{Reference: "order-1", Amount: 100}); err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	sim.Reset()
+
+	if _, err := sim.Capture(context.Background(), "order-1", 100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Capture() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := sim.Refund(context.Background(), RefundRequest{Reference: "order-1", RefundReference: "refund-1", Amount: 100}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Refund() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := sim.Query(context.Background(), "order-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Query() error = %v, want %v", err, ErrNotFound)
+	}
+}
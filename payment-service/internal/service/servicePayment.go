@@ -1,19 +1,50 @@
 package service
 
-import "order-microsystem/payment-service/internal/domain/model"
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"order-microsystem/payment-service/internal/domain/model"
+	"order-microsystem/payment-service/internal/domain/repository"
+	"order-microsystem/payment-service/internal/provider"
+	"order-microsystem/payment-service/pkg/messaging"
+	"strconv"
+	"unicode/utf8"
+)
 
 type PaymentRepository interface {
 	CreatePayment(paymentModel *model.PaymentModel) error
-	GetPayment(user_id string) (*model.PaymentModel, error)
-	GetAllPayment(user_id string) ([]*model.PaymentModel, error)
+	GetPaymentByPaymentID(paymentID string) (*model.PaymentModel, error)
+	GetPaymentByOrderID(orderID string) (*model.PaymentModel, error)
+	ListPayments(filter model.PaymentFilter, beforeID uint, limit int) ([]*model.PaymentModel, error)
+	CreatePendingRefund(paymentID uint, refund *model.RefundModel) (*model.RefundModel, error)
+	ListPendingRefunds(paymentID uuid.UUID) ([]*model.RefundModel, error)
+	CompleteRefund(paymentID uint, refundID uint, newEvent func(payment *model.PaymentModel) (*model.OutboxEvent, error)) (*model.PaymentModel, *model.RefundModel, error)
+	FailRefund(refundID uint) error
 }
 
+const (
+	// maxRefundReasonLength 退款原因的最大长度，与 refund_models.reason 列宽一致
+	maxRefundReasonLength = 255
+	// maxIdempotencyKeyLength 退款幂等键的最大长度，与 refund_models.idempotency_key 列宽一致
+	maxIdempotencyKeyLength = 128
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type PaymentService struct {
-	repo PaymentRepository
+	repo     PaymentRepository
+	provider provider.PaymentProvider
 }
 
-func NewPaymentService(repo PaymentRepository) *PaymentService {
-	return &PaymentService{repo: repo}
+func NewPaymentService(repo PaymentRepository, paymentProvider provider.PaymentProvider) *PaymentService {
+	return &PaymentService{repo: repo, provider: paymentProvider}
 }
 
 func (s *PaymentService) CreatePayment(model *model.PaymentModel) error {
@@ -23,18 +54,201 @@ func (s *PaymentService) CreatePayment(model *model.PaymentModel) error {
 	return nil
 }
 
-func (s *PaymentService) GetPayment(user_id string) (*model.PaymentModel, error) {
-	result, err := s.repo.GetPayment(user_id)
+// GetPayment 按支付ID查询支付记录
+func (s *PaymentService) GetPayment(paymentID string) (*model.PaymentModel, error) {
+	if _, err := uuid.Parse(paymentID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid payment_id: %s", paymentID)
+	}
+	payment, err := s.repo.GetPaymentByPaymentID(paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "payment %s not found", paymentID)
+		}
+		return nil, fmt.Errorf("failed to get payment: %v", err)
+	}
+	return payment, nil
+}
+
+// GetPaymentByOrder 查询订单的支付记录，订单尚未发起支付时返回 NotFound
+func (s *PaymentService) GetPaymentByOrder(orderID string) (*model.PaymentModel, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order_id: %s", orderID)
+	}
+	payment, err := s.repo.GetPaymentByOrderID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "payment of order %s not found", orderID)
+		}
+		return nil, fmt.Errorf("failed to get payment: %v", err)
+	}
+	return payment, nil
+}
+
+// GetAllPayment 按过滤条件分页查询支付记录，返回当前页支付记录和下一页的游标
+func (s *PaymentService) GetAllPayment(filter model.PaymentFilter, pageSize int32, pageToken string) ([]*model.PaymentModel, string, error) {
+	if filter.UserID != "" {
+		if _, err := uuid.Parse(filter.UserID); err != nil {
+			return nil, "", status.Errorf(codes.InvalidArgument, "invalid user_id: %v", err)
+		}
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid status: %s", filter.Status)
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	beforeID, err := decodePageToken(pageToken)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	// 多查一条用于判断是否还有下一页
+	payments, err := s.repo.ListPayments(filter, beforeID, int(pageSize)+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list payments: %v", err)
+	}
+	if len(payments) <= int(pageSize) {
+		return payments, "", nil
+	}
+
+	payments = payments[:pageSize]
+	return payments, encodePageToken(payments[len(payments)-1].ID), nil
+}
+
+func encodePageToken(lastID uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(lastID), 10)))
+}
+
+func decodePageToken(token string) (uint, error) {
+	if token == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return result, nil
+	id, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return uint(id), nil
 }
 
-func (s *PaymentService) GetAllPayment(user_id string) ([]*model.PaymentModel, error) {
-	results, err := s.repo.GetAllPayment(user_id)
+// RefundPayment 退回支付中 amount 的金额：先写入 pending 的退款记录占用可退款金额，再以退款ID作为退款流水号调用支付渠道，
+// 渠道确认后将退款记录、累计退款金额与 payment.refunded 事件在同一事务中写入。累计退款金额不能超过支付金额，超过时返回 FailedPrecondition。
+// idempotencyKey 不为空时，同一支付下相同的键只会退款一次，渠道超时后以相同的键重试会继续处理原来的退款
+func (s *PaymentService) RefundPayment(ctx context.Context, paymentID string, amount int64, reason string, idempotencyKey string) (*model.PaymentModel, *model.RefundModel, error) {
+	if _, err := uuid.Parse(paymentID); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid payment_id: %s", paymentID)
+	}
+	if amount <= 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "amount must be positive")
+	}
+	if utf8.RuneCountInString(reason) > maxRefundReasonLength {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "reason must be at most %d characters", maxRefundReasonLength)
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "idempotency key exceeds %d characters", maxIdempotencyKeyLength)
+	}
+
+	payment, err := s.repo.GetPaymentByPaymentID(paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, status.Errorf(codes.NotFound, "payment %s not found", paymentID)
+		}
+		return nil, nil, fmt.Errorf("failed to get payment: %v", err)
+	}
+
+	// 先处理此前因渠道超时等原因未完成的其他退款，渠道按退款流水号去重，不会重复退款
+	pending, err := s.repo.ListPendingRefunds(payment.PaymentID)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to list pending refunds: %v", err)
+	}
+	for _, refund := range pending {
+		if idempotencyKey != "" && refund.IdempotencyKey == idempotencyKey {
+			continue
+		}
+		if _, _, err := s.settleRefund(ctx, payment, refund); err != nil && status.Code(err) != codes.FailedPrecondition {
+			return nil, nil, err
+		}
+	}
+
+	refund, err := s.repo.CreatePendingRefund(payment.ID, &model.RefundModel{
+		RefundID:       uuid.New(),
+		PaymentID:      payment.PaymentID,
+		Amount:         amount,
+		Reason:         reason,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return nil, nil, refundStatusError(paymentID, amount, err)
+	}
+	if refund.Amount != amount {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "idempotency key %s was already used for a refund of %d", idempotencyKey, refund.Amount)
+	}
+	switch refund.Status {
+	case model.RefundStatusSucceeded:
+		// 相同幂等键的重试返回已完成的退款
+		payment, err := s.repo.GetPaymentByPaymentID(paymentID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get payment: %v", err)
+		}
+		return payment, refund, nil
+	case model.RefundStatusFailed:
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "refund %s was declined by the payment provider", refund.RefundID)
+	}
+	return s.settleRefund(ctx, payment, refund)
+}
+
+// settleRefund 以退款ID作为退款流水号调用支付渠道处理 pending 的退款，渠道确认后完成退款，渠道拒绝时将退款置为 failed。
+// 渠道超时等结果未知的错误保留 pending 的退款并返回 Unavailable。早于渠道接入的支付没有渠道交易，直接完成退款
+func (s *PaymentService) settleRefund(ctx context.Context, payment *model.PaymentModel, refund *model.RefundModel) (*model.PaymentModel, *model.RefundModel, error) {
+	if payment.TransactionID != "" {
+		transaction, err := s.provider.Refund(ctx, provider.RefundRequest{
+			Reference:       payment.OrderID.String(),
+			RefundReference: refund.RefundID.String(),
+			Amount:          refund.Amount,
+		})
+		var rejected error
+		switch {
+		case errors.Is(err, provider.ErrInvalidState), errors.Is(err, provider.ErrNotFound):
+			rejected = status.Errorf(codes.FailedPrecondition, "refund rejected by %s: %v", s.provider.Name(), err)
+		case err != nil:
+			return nil, nil, status.Errorf(codes.Unavailable, "failed to refund payment, refund %s is pending: %v", refund.RefundID, err)
+		case transaction.Declined():
+			rejected = status.Errorf(codes.FailedPrecondition, "refund declined by %s: %s", s.provider.Name(), transaction.DeclineReason)
+		}
+		if rejected != nil {
+			if err := s.repo.FailRefund(refund.ID); err != nil {
+				return nil, nil, fmt.Errorf("failed to mark refund %s failed: %v", refund.RefundID, err)
+			}
+			return nil, nil, rejected
+		}
+	}
+
+	refunded, completed, err := s.repo.CompleteRefund(payment.ID, refund.ID, func(payment *model.PaymentModel) (*model.OutboxEvent, error) {
+		return messaging.NewPaymentRefundedEvent(payment, refund)
+	})
+	if err != nil {
+		return nil, nil, refundStatusError(payment.PaymentID.String(), refund.Amount, err)
+	}
+	return refunded, completed, nil
+}
+
+// refundStatusError 将仓储返回的退款错误转换为 gRPC 状态
+func refundStatusError(paymentID string, amount int64, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrRefundExceedsPayment):
+		return status.Errorf(codes.FailedPrecondition, "refund amount %d exceeds refundable amount", amount)
+	case errors.Is(err, repository.ErrPaymentNotRefundable):
+		return status.Errorf(codes.FailedPrecondition, "payment %s cannot be refunded", paymentID)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Errorf(codes.NotFound, "payment %s not found", paymentID)
+	default:
+		return err
 	}
-	return results, nil
 }
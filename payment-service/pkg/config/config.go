@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type ServerConfig struct {
@@ -40,6 +41,23 @@ type JaegerConfig struct {
 	ServiceName string `mapstructure:"service_name"`
 }
 
+type PaymentConfig struct {
+	// Provider 支付渠道，目前支持 simulator
+	Provider string `mapstructure:"provider"`
+	// Timeout 单次调用支付渠道的超时时间
+	Timeout   time.Duration   `mapstructure:"timeout"`
+	Simulator SimulatorConfig `mapstructure:"simulator"`
+}
+
+type SimulatorConfig struct {
+	// Latency 每次调用的模拟延迟
+	Latency time.Duration `mapstructure:"latency"`
+	// Timeout 模拟超时的调用在返回前等待的时间
+	Timeout time.Duration `mapstructure:"timeout"`
+	// DeclineAbove 授权金额超过该值时拒绝，为 0 表示不拒绝
+	DeclineAbove int64 `mapstructure:"decline_above"`
+}
+
 type Config struct {
 	Server   ServerConfig `mapstructure:"server"`
 	Database struct {
@@ -48,6 +66,7 @@ type Config struct {
 	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
 	Consul   ConsulConfig   `mapstructure:"consul"`
 	Jaeger   JaegerConfig   `mapstructure:"jaeger"`
+	Payment  PaymentConfig  `mapstructure:"payment"`
 }
 
 func NewConfig(path string) (*Config, error) {
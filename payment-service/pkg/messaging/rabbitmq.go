@@ -3,12 +3,15 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
 	"log"
 	"order-microsystem/payment-service/internal/domain/model"
 	"order-microsystem/payment-service/internal/domain/repository"
+	"order-microsystem/payment-service/internal/provider"
 	"order-microsystem/payment-service/pkg/config"
 	"time"
 )
@@ -18,9 +21,15 @@ type RabbitMQ struct {
 	ch     *amqp091.Channel
 	config *config.RabbitMQConfig
 	repo   *repository.MySQLRepository
+	// provider 扣款与退款使用的支付渠道，providerTimeout 为单次调用的超时时间
+	provider        provider.PaymentProvider
+	providerTimeout time.Duration
 }
 
-func NewRabbitMQ(config *config.RabbitMQConfig, repo *repository.MySQLRepository) (*RabbitMQ, error) {
+// defaultProviderTimeout 未配置超时时间时调用支付渠道的超时时间
+const defaultProviderTimeout = 10 * time.Second
+
+func NewRabbitMQ(config *config.RabbitMQConfig, repo *repository.MySQLRepository, paymentProvider provider.PaymentProvider, providerTimeout time.Duration) (*RabbitMQ, error) {
 	url := fmt.Sprintf("amqp://%s:%s@%s:%d", config.Username, config.Password, config.Host, config.Port)
 	var conn *amqp091.Connection
 	var err error
@@ -54,11 +63,16 @@ func NewRabbitMQ(config *config.RabbitMQConfig, repo *repository.MySQLRepository
 		return nil, fmt.Errorf("failed to declare exchange: %v", err)
 	}
 
+	if providerTimeout <= 0 {
+		providerTimeout = defaultProviderTimeout
+	}
 	return &RabbitMQ{
-		conn:   conn,
-		ch:     channel,
-		config: config,
-		repo:   repo,
+		conn:            conn,
+		ch:              channel,
+		config:          config,
+		repo:            repo,
+		provider:        paymentProvider,
+		providerTimeout: providerTimeout,
 	}, nil
 }
 
@@ -69,41 +83,41 @@ func (rmq *RabbitMQ) Close() error {
 	return rmq.conn.Close()
 }
 
-func (rmq *RabbitMQ) ConsumeInventoryLocked() {
-	// 声明一个匿名队列
+// queuePrefix 本服务所有消费队列名称的前缀
+const queuePrefix = "payment-service."
+
+// consume 声明持久化的具名队列并绑定路由键后开始消费。服务停机期间投递的消息会保留在队列中，
+// 多个副本共享同一队列时每条消息只会被其中一个副本处理
+func (rmq *RabbitMQ) consume(name string, routingKeys ...string) (<-chan amqp091.Delivery, error) {
 	q, err := rmq.ch.QueueDeclare(
-		"",
-		false,
-		false,
-		true,
-		false,
-		nil,
+		queuePrefix+name, // 队列名称
+		true,             // 持久化
+		false,            // 不自动删除
+		false,            // 非排他性
+		false,            // 不等待
+		nil,              // 额外参数
 	)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("failed to declare queue: %v", err)
 	}
 
-	err = rmq.ch.QueueBind(
-		q.Name,
-		"inventory.locked",
-		rmq.config.Exchange,
-		false,
-		nil,
-	)
+	for _, routingKey := range routingKeys {
+		if err := rmq.ch.QueueBind(q.Name, routingKey, rmq.config.Exchange, false, nil); err != nil {
+			return nil, fmt.Errorf("failed to bind queue: %v", err)
+		}
+	}
+
+	return rmq.ch.Consume(q.Name, "", false, false, false, false, nil)
+}
+
+// ConsumeInventoryLocked 消费库存锁定(inventory.locked)事件并对订单扣款。消息处理失败时重新入队，
+// 同一订单的消息可能被重复投递，由收件箱与 order_id 上的唯一索引保证每个订单只扣款一次
+func (rmq *RabbitMQ) ConsumeInventoryLocked() {
+	msgs, err := rmq.consume("inventory.locked", "inventory.locked")
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	msgs, err := rmq.ch.Consume(
-		q.Name,
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
-
 	for msg := range msgs {
 		var receive_msg struct {
 			EventType  string    `json:"event_type"`
@@ -112,27 +126,197 @@ func (rmq *RabbitMQ) ConsumeInventoryLocked() {
 			TotalPrice int64     `json:"total_price"`
 		}
 		if err := json.Unmarshal(msg.Body, &receive_msg); err != nil {
-			log.Fatal(err.Error())
-			msg.Nack(false, true)
+			log.Printf("failed to unmarshal inventory locked event: %v", err)
+			msg.Nack(false, false)
 			continue
 		}
 
-		payment := &model.PaymentModel{
-			PaymentID:  uuid.New(),
-			OrderID:    receive_msg.OrderID,
-			UserID:     receive_msg.UserID,
-			TotalPrice: receive_msg.TotalPrice,
-		}
-		if err := rmq.repo.CreatePayment(payment); err != nil {
-			log.Fatal("failed to create payment: %v", err)
+		inbox := newInboxMessage(msg)
+		err := rmq.chargeOrder(inbox, receive_msg.OrderID, receive_msg.UserID, receive_msg.TotalPrice)
+		if err != nil {
+			log.Printf("failed to create payment for order %s: %v", receive_msg.OrderID, err)
+			msg.Nack(false, true)
 			continue
 		}
-		rmq.PublishPaymentCompleted(payment)
 		msg.Ack(false)
 	}
 }
 
-func (rmq *RabbitMQ) PublishPaymentCompleted(payment *model.PaymentModel) error {
+// newInboxMessage 构建消息的处理记录，没有 MessageId 的消息无法去重，返回 nil
+func newInboxMessage(msg amqp091.Delivery) *model.InboxMessage {
+	if msg.MessageId == "" {
+		return nil
+	}
+	return &model.InboxMessage{
+		MessageID:   msg.MessageId,
+		RoutingKey:  msg.RoutingKey,
+		ProcessedAt: time.Now(),
+	}
+}
+
+const (
+	// FailureCodeInvalidRequest 支付渠道认为扣款请求本身无效(如金额不合法)，重试也不会成功
+	FailureCodeInvalidRequest = "invalid_request"
+	// FailureCodeTransactionNotFound 支付渠道中已没有授权的交易(如模拟渠道重启后丢失)，重试也不会成功
+	FailureCodeTransactionNotFound = "transaction_not_found"
+)
+
+// chargeOrder 先写入 pending 的支付记录，再通过支付渠道授权并扣款，每一步的结果都以 UpdatePaymentStatus 落库：
+// 授权后置为 authorized，扣款后置为 captured 并与 payment.completed 事件在同一事务中写入；
+// 渠道拒绝时置为 failed 并写入 payment.failed 事件。渠道超时等可重试的错误直接返回，由消息重新投递后从当前状态继续。
+// 得出扣款结果时与 inbox 一同写入，已处理过的消息及同一订单的其他消息只会重新发布已有的支付结果。
+// 渠道以订单ID作为商户流水号，重复投递不会重复授权或扣款
+func (rmq *RabbitMQ) chargeOrder(inbox *model.InboxMessage, orderID uuid.UUID, userID uuid.UUID, totalPrice int64) error {
+	if inbox != nil {
+		processed, err := rmq.repo.IsMessageProcessed(inbox.MessageID)
+		if err != nil {
+			return err
+		}
+		if processed {
+			payment, err := rmq.repo.GetPaymentByOrderID(orderID.String())
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				return nil
+			case err != nil:
+				return fmt.Errorf("failed to query payment: %v", err)
+			}
+			return rmq.publishPaymentResult(payment, nil)
+		}
+	}
+
+	payment, err := rmq.repo.GetOrCreatePayment(&model.PaymentModel{
+		PaymentID:  uuid.New(),
+		OrderID:    orderID,
+		UserID:     userID,
+		TotalPrice: totalPrice,
+		Provider:   rmq.provider.Name(),
+		Status:     model.PaymentStatusPending,
+	})
+	if err != nil {
+		return err
+	}
+
+	if payment.Status.Paid() || payment.Status == model.PaymentStatusFailed {
+		// 订单已有扣款结果，不再重复扣款
+		return rmq.publishPaymentResult(payment, inbox)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rmq.providerTimeout)
+	defer cancel()
+
+	failureCode, err := chargePayment(ctx, rmq.provider, payment, func() error {
+		return rmq.setPaymentStatus(payment, model.PaymentStatusAuthorized, nil)
+	})
+	if err != nil {
+		return err
+	}
+	if failureCode != "" {
+		return rmq.failPayment(payment, failureCode, inbox)
+	}
+	event, err := NewPaymentCompletedEvent(payment)
+	if err != nil {
+		return err
+	}
+	return rmq.setPaymentStatus(payment, model.PaymentStatusCaptured, inbox, event)
+}
+
+// chargePayment 通过支付渠道对支付授权并扣款，渠道以订单ID作为商户流水号。pending 的支付先授权，
+// 授权成功后调用 authorized 记录授权结果再扣款，authorized 的支付直接扣款。
+// 返回的失败原因代码不为空表示扣款已确定失败；渠道超时等可重试的错误以 error 返回，重试时不会重复授权或扣款
+func chargePayment(ctx context.Context, p provider.PaymentProvider, payment *model.PaymentModel, authorized func() error) (string, error) {
+	reference := payment.OrderID.String()
+	if payment.Status == model.PaymentStatusPending {
+		transaction, err := p.Authorize(ctx, provider.AuthorizeRequest{
+			Reference:  reference,
+			CustomerID: payment.UserID.String(),
+			Amount:     payment.TotalPrice,
+		})
+		if errors.Is(err, provider.ErrInvalidState) {
+			return FailureCodeInvalidRequest, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to authorize payment: %w", err)
+		}
+		payment.TransactionID = transaction.TransactionID
+		if transaction.Declined() {
+			return transaction.DeclineReason, nil
+		}
+		if err := authorized(); err != nil {
+			return "", err
+		}
+	}
+
+	transaction, err := p.Capture(ctx, reference, payment.TotalPrice)
+	switch {
+	case errors.Is(err, provider.ErrInvalidState):
+		return FailureCodeInvalidRequest, nil
+	case errors.Is(err, provider.ErrNotFound):
+		return FailureCodeTransactionNotFound, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to capture payment: %w", err)
+	case transaction.Declined():
+		return transaction.DeclineReason, nil
+	}
+	if payment.TransactionID == "" {
+		payment.TransactionID = transaction.TransactionID
+	}
+	return "", nil
+}
+
+// failPayment 在渠道拒绝授权或扣款时将支付置为 failed，并写入 payment.failed 事件
+func (rmq *RabbitMQ) failPayment(payment *model.PaymentModel, failureCode string, inbox *model.InboxMessage) error {
+	payment.FailureCode = failureCode
+	log.Printf("payment of order %s failed at %s: %s", payment.OrderID, rmq.provider.Name(), payment.FailureCode)
+
+	event, err := NewPaymentFailedEvent(payment)
+	if err != nil {
+		return err
+	}
+	return rmq.setPaymentStatus(payment, model.PaymentStatusFailed, inbox, event)
+}
+
+// setPaymentStatus 将支付从当前状态置为 status，events 与 inbox 不为 nil 时的消息处理记录与状态变更一同写入。
+// 支付已被并发处理的消息推进时返回错误，由消息重新投递后按最新状态处理
+func (rmq *RabbitMQ) setPaymentStatus(payment *model.PaymentModel, status model.PaymentStatus, inbox *model.InboxMessage, events ...*model.OutboxEvent) error {
+	from := payment.Status
+	payment.SetStatus(status, time.Now())
+	return rmq.repo.Transaction(func(txRepo *repository.MySQLRepository) error {
+		updated, err := txRepo.UpdatePaymentStatus(payment, from, events...)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return fmt.Errorf("payment %s is no longer %s", payment.PaymentID, from)
+		}
+		if inbox == nil {
+			return nil
+		}
+		return txRepo.MarkMessageProcessed(inbox)
+	})
+}
+
+// publishPaymentResult 重新发布支付已有的扣款结果，inbox 不为 nil 时与消息处理记录在同一事务中写入。
+// 已全额退款的支付不再重新发布
+func (rmq *RabbitMQ) publishPaymentResult(payment *model.PaymentModel, inbox *model.InboxMessage) error {
+	return rmq.repo.Transaction(func(txRepo *repository.MySQLRepository) error {
+		if payment.Status != model.PaymentStatusRefunded {
+			event, err := newPaymentResultEvent(payment)
+			if err != nil {
+				return err
+			}
+			if err := txRepo.AddOutboxEvent(event); err != nil {
+				return err
+			}
+		}
+		if inbox == nil {
+			return nil
+		}
+		return txRepo.MarkMessageProcessed(inbox)
+	})
+}
+
+// NewPaymentCompletedEvent 构建支付完成事件，订单服务据此完成订单
+func NewPaymentCompletedEvent(payment *model.PaymentModel) (*model.OutboxEvent, error) {
 	event := map[string]interface{}{
 		"event_type":  "payment_completed",
 		"payment_id":  payment.PaymentID,
@@ -141,28 +325,168 @@ func (rmq *RabbitMQ) PublishPaymentCompleted(payment *model.PaymentModel) error
 		"total_price": payment.TotalPrice,
 	}
 
-	// 序列化
-	body, err := json.Marshal(&event)
+	return newOutboxEvent("payment.completed", event)
+}
+
+// NewPaymentFailedEvent 构建支付失败事件，订单服务据此将订单置为 payment_failed，库存服务归还订单锁定的库存
+func NewPaymentFailedEvent(payment *model.PaymentModel) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type":  "payment_failed",
+		"payment_id":  payment.PaymentID,
+		"order_id":    payment.OrderID,
+		"user_id":     payment.UserID,
+		"total_price": payment.TotalPrice,
+		"reason_code": payment.FailureCode,
+	}
+
+	return newOutboxEvent("payment.failed", event)
+}
+
+// NewPaymentRefundedEvent 构建退款事件，refunded_amount 为本次退款后支付的累计退款金额
+func NewPaymentRefundedEvent(payment *model.PaymentModel, refund *model.RefundModel) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type":      "payment_refunded",
+		"payment_id":      payment.PaymentID,
+		"order_id":        payment.OrderID,
+		"user_id":         payment.UserID,
+		"refund_id":       refund.RefundID,
+		"amount":          refund.Amount,
+		"reason":          refund.Reason,
+		"refunded_amount": payment.RefundedAmount + refund.Amount,
+	}
+
+	return newOutboxEvent("payment.refunded", event)
+}
+
+// newPaymentResultEvent 按支付记录的状态构建 payment.completed 或 payment.failed 事件
+func newPaymentResultEvent(payment *model.PaymentModel) (*model.OutboxEvent, error) {
+	if payment.Status == model.PaymentStatusFailed {
+		return NewPaymentFailedEvent(payment)
+	}
+	return NewPaymentCompletedEvent(payment)
+}
+
+// ConsumeOrderCancelled 消费订单取消(order.cancelled)与订单超时(order.expired)事件，对该订单已创建的支付退款并回传 payment.cancelled 确认
+func (rmq *RabbitMQ) ConsumeOrderCancelled() {
+	// 超时取消的订单与主动取消的订单按相同方式处理
+	msgs, err := rmq.consume("order.cancellations", "order.cancelled", "order.expired")
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %v", err)
+		log.Fatal(err.Error())
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	for msg := range msgs {
+		var receive_msg struct {
+			EventType string    `json:"event_type"`
+			OrderID   uuid.UUID `json:"order_id"`
+		}
+		if err := json.Unmarshal(msg.Body, &receive_msg); err != nil {
+			log.Printf("failed to unmarshal %s event: %v", msg.RoutingKey, err)
+			msg.Nack(false, false)
+			continue
+		}
+
+		err := rmq.cancelPayment(receive_msg.OrderID)
+		if errors.Is(err, provider.ErrNotFound) || errors.Is(err, provider.ErrInvalidState) {
+			// 渠道中已没有可退款的交易(如模拟渠道重启后丢失)，重试也不会成功，丢弃消息并留待人工处理，
+			// 订单服务的对账任务会按退避间隔重新发布取消事件
+			log.Printf("failed to cancel payment of order %s, manual refund required: %v", receive_msg.OrderID, err)
+			msg.Nack(false, false)
+			continue
+		}
+		if err != nil {
+			log.Printf("failed to cancel payment of order %s: %v", receive_msg.OrderID, err)
+			msg.Nack(false, true)
+			continue
+		}
+		msg.Ack(false)
+	}
+}
+
+// cancelPayment 通过支付渠道退回订单支付中尚未退款的金额，再将退款记录与 payment.cancelled 确认在同一事务中写入
+func (rmq *RabbitMQ) cancelPayment(orderID uuid.UUID) error {
+	payment, err := rmq.repo.GetPaymentByOrderID(orderID.String())
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// 订单尚未支付，无需退款
+		payment = nil
+	case err != nil:
+		return fmt.Errorf("failed to query payment: %v", err)
+	case !payment.Status.Refundable():
+		// 尚未扣款、扣款失败或已全额退款(包括重复投递的取消事件)的订单没有需要退款的金额
+		payment = nil
+	}
+	if payment == nil {
+		event, err := NewPaymentCancelledEvent(orderID, 0)
+		if err != nil {
+			return err
+		}
+		return rmq.repo.AddOutboxEvent(event)
+	}
+
+	refund := &model.RefundModel{
+		RefundID:  uuid.New(),
+		PaymentID: payment.PaymentID,
+		Amount:    payment.TotalPrice - payment.RefundedAmount,
+		Reason:    "order cancelled",
+	}
+	// 退款流水号由订单ID与退款前的累计退款金额派生，重复投递的取消事件不会在渠道侧重复退款
+	refundReference := fmt.Sprintf("cancel-%s-%d", orderID, payment.RefundedAmount)
+	ctx, cancel := context.WithTimeout(context.Background(), rmq.providerTimeout)
 	defer cancel()
+	if err := refundPayment(ctx, rmq.provider, payment, refundReference, refund.Amount); err != nil {
+		return err
+	}
 
-	err = rmq.ch.PublishWithContext(ctx,
-		rmq.config.Exchange,
-		"payment.completed",
-		false,
-		false,
-		amqp091.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to publish message: %v", err)
+	_, err = rmq.repo.AddRefund(payment.ID, refund, func(*model.PaymentModel) (*model.OutboxEvent, error) {
+		return NewPaymentCancelledEvent(orderID, refund.Amount)
+	})
+	return err
+}
+
+// refundPayment 通过支付渠道退回支付中 amount 的金额，早于渠道接入的支付没有渠道交易，只需记录退款。
+// 渠道返回的 ErrNotFound、ErrInvalidState 会被包装返回，调用方可据此区分无法重试的错误
+func refundPayment(ctx context.Context, p provider.PaymentProvider, payment *model.PaymentModel, refundReference string, amount int64) error {
+	if payment.TransactionID == "" {
+		return nil
 	}
 
+	transaction, err := p.Refund(ctx, provider.RefundRequest{
+		Reference:       payment.OrderID.String(),
+		RefundReference: refundReference,
+		Amount:          amount,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+	if transaction.Declined() {
+		return fmt.Errorf("refund declined by %s: %s", p.Name(), transaction.DeclineReason)
+	}
 	return nil
 }
+
+// NewPaymentCancelledEvent 构建支付撤销确认事件，refunded_amount 为 0 表示没有需要退款的支付
+func NewPaymentCancelledEvent(orderID uuid.UUID, refundedAmount int64) (*model.OutboxEvent, error) {
+	event := map[string]interface{}{
+		"event_type":      "payment_cancelled",
+		"order_id":        orderID,
+		"refunded_amount": refundedAmount,
+	}
+
+	return newOutboxEvent("payment.cancelled", event)
+}
+
+// newOutboxEvent 将事件序列化为 JSON 并生成唯一的事件ID，该ID会作为消息的 MessageId 投递
+func newOutboxEvent(routingKey string, event interface{}) (*model.OutboxEvent, error) {
+	// 序列化
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	return &model.OutboxEvent{
+		ID:         uuid.New().String(),
+		RoutingKey: routingKey,
+		Payload:    string(body),
+		CreatedAt:  time.Now(),
+	}, nil
+}
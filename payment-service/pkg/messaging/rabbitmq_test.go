@@ -0,0 +1,266 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"order-microsystem/payment-service/internal/domain/model"
+	"order-microsystem/payment-service/internal/provider"
+	"order-microsystem/payment-service/pkg/config"
+	"testing"
+	"time"
+)
+
+func newTestPayment(status model.PaymentStatus, totalPrice int64) *model.PaymentModel {
+	return &model.PaymentModel{
+		PaymentID:  uuid.New(),
+		OrderID:    uuid.New(),
+		UserID:     uuid.New(),
+		TotalPrice: totalPrice,
+		Status:     status,
+	}
+}
+
+func TestChargePayment(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.SimulatorConfig
+		// authorizeFirst 为 true 时支付已在渠道中授权并置为 authorized
+		authorizeFirst  bool
+		totalPrice      int64
+		scripts         map[provider.Operation][]provider.Outcome
+		wantFailureCode string
+		wantAuthorized  bool
+		wantStatus      provider.Status
+	}{
+		{name: "captured", totalPrice: 100, wantAuthorized: true, wantStatus: provider.StatusCaptured},
+		{name: "authorize declined", totalPrice: 100,
+			scripts:         map[provider.Operation][]provider.Outcome{provider.OpAuthorize: {{Behavior: provider.BehaviorDecline, Reason: "insufficient_funds"}}},
+			wantFailureCode: "insufficient_funds", wantStatus: provider.StatusDeclined},
+		{name: "amount above limit", cfg: config.SimulatorConfig{DeclineAbove: 50}, totalPrice: 100,
+			wantFailureCode: "amount_limit_exceeded", wantStatus: provider.StatusDeclined},
+		{name: "capture declined", totalPrice: 100,
+			scripts:         map[provider.Operation][]provider.Outcome{provider.OpCapture: {{Behavior: provider.BehaviorDecline, Reason: "card_expired"}}},
+			wantFailureCode: "card_expired", wantAuthorized: true, wantStatus: provider.StatusAuthorized},
+		{name: "invalid amount", totalPrice: 0, wantFailureCode: FailureCodeInvalidRequest},
+		{name: "resume from authorized", authorizeFirst: true, totalPrice: 100, wantStatus: provider.StatusCaptured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := provider.NewSimulator(tt.cfg)
+			payment := newTestPayment(model.PaymentStatusPending, tt.totalPrice)
+			if tt.authorizeFirst {
+				if _, err := sim.Authorize(context.Background(), provider.AuthorizeRequest{Reference: payment.OrderID.String(), Amount: tt.totalPrice}); err != nil {
+					t.Fatalf("Authorize() error = %v", err)
+				}
+				payment.SetStatus(model.PaymentStatusAuthorized, time.Now())
+			}
+			for op, outcomes := range tt.scripts {
+				sim.Script(op, outcomes...)
+			}
+
+			authorized := false
+			failureCode, err := chargePayment(context.Background(), sim, payment, func() error {
+				authorized = true
+				payment.SetStatus(model.PaymentStatusAuthorized, time.Now())
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("chargePayment() error = %v", err)
+			}
+			if failureCode != tt.wantFailureCode {
+				t.Errorf("chargePayment() failure code = %q, want %q", failureCode, tt.wantFailureCode)
+			}
+			if authorized != tt.wantAuthorized {
+				t.Errorf("authorized callback called = %v, want %v", authorized, tt.wantAuthorized)
+			}
+			if tt.wantStatus == "" {
+				return
+			}
+			tx, err := sim.Query(context.Background(), payment.OrderID.String())
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if tx.Status != tt.wantStatus {
+				t.Errorf("transaction status = %s, want %s", tx.Status, tt.wantStatus)
+			}
+			if payment.TransactionID != tx.TransactionID {
+				t.Errorf("payment transaction id = %q, want %q", payment.TransactionID, tx.TransactionID)
+			}
+		})
+	}
+}
+
+// TestChargePaymentTimeoutThenRetry 渠道超时时返回可重试的错误，消息重新投递后从支付当前的状态继续，只授权与扣款一次
+func TestChargePaymentTimeoutThenRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		op   provider.Operation
+		// wantStatusAfterTimeout 超时后支付记录的状态
+		wantStatusAfterTimeout model.PaymentStatus
+	}{
+		{name: "authorize timeout", op: provider.OpAuthorize, wantStatusAfterTimeout: model.PaymentStatusPending},
+		{name: "capture timeout", op: provider.OpCapture, wantStatusAfterTimeout: model.PaymentStatusAuthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := provider.NewSimulator(config.SimulatorConfig{})
+			sim.Script(tt.op, provider.Outcome{Behavior: provider.BehaviorTimeout})
+			payment := newTestPayment(model.PaymentStatusPending, 100)
+			authorized := func() error {
+				payment.SetStatus(model.PaymentStatusAuthorized, time.Now())
+				return nil
+			}
+
+			if _, err := chargePayment(context.Background(), sim, payment, authorized); !errors.Is(err, provider.ErrTimeout) {
+				t.Fatalf("first chargePayment() error = %v, want %v", err, provider.ErrTimeout)
+			}
+			if payment.Status != tt.wantStatusAfterTimeout {
+				t.Fatalf("payment status after timeout = %s, want %s", payment.Status, tt.wantStatusAfterTimeout)
+			}
+
+			failureCode, err := chargePayment(context.Background(), sim, payment, authorized)
+			if err != nil || failureCode != "" {
+				t.Fatalf("retry chargePayment() = %q, %v, want success", failureCode, err)
+			}
+			tx, err := sim.Query(context.Background(), payment.OrderID.String())
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if tx.Status != provider.StatusCaptured || tx.CapturedAmount != 100 {
+				t.Errorf("transaction = %s captured %d, want captured 100", tx.Status, tx.CapturedAmount)
+			}
+		})
+	}
+}
+
+// TestChargePaymentTransactionLost 已授权的交易在渠道中丢失(如模拟渠道重启)时扣款确定失败，不再重试
+func TestChargePaymentTransactionLost(t *testing.T) {
+	sim := provider.NewSimulator(config.SimulatorConfig{})
+	payment := newTestPayment(model.PaymentStatusAuthorized, 100)
+	payment.TransactionID = "sim_lost"
+
+	failureCode, err := chargePayment(context.Background(), sim, payment, func() error {
+		t.Fatal("authorized callback called for an authorized payment")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chargePayment() error = %v", err)
+	}
+	if failureCode != FailureCodeTransactionNotFound {
+		t.Errorf("chargePayment() failure code = %q, want %q", failureCode, FailureCodeTransactionNotFound)
+	}
+}
+
+func TestRefundPayment(t *testing.T) {
+	type refund struct {
+		reference string
+		amount    int64
+		script    []provider.Outcome
+		wantErr   error
+		// wantFail 为 true 时只要求返回错误，用于渠道拒绝等没有哨兵错误的情况
+		wantFail bool
+	}
+	tests := []struct {
+		name string
+		// lost 为 true 时渠道中没有该支付的交易
+		lost          bool
+		legacy        bool
+		refunds       []refund
+		wantRefunded  int64
+		wantRefundAPI int
+	}{
+		{
+			name:          "partial refunds up to the captured amount",
+			refunds:       []refund{{reference: "r1", amount: 30}, {reference: "r2", amount: 70}},
+			wantRefunded:  100,
+			wantRefundAPI: 2,
+		},
+		{
+			name:          "exceeds refundable amount",
+			refunds:       []refund{{reference: "r1", amount: 60}, {reference: "r2", amount: 50, wantErr: provider.ErrInvalidState}},
+			wantRefunded:  60,
+			wantRefundAPI: 2,
+		},
+		{
+			name:          "declined",
+			refunds:       []refund{{reference: "r1", amount: 60, script: []provider.Outcome{{Behavior: provider.BehaviorDecline}}, wantFail: true}},
+			wantRefunded:  0,
+			wantRefundAPI: 1,
+		},
+		{
+			name: "timeout then retry with the same reference",
+			refunds: []refund{
+				{reference: "r1", amount: 60, script: []provider.Outcome{{Behavior: provider.BehaviorTimeout}}, wantErr: provider.ErrTimeout},
+				{reference: "r1", amount: 60},
+			},
+			wantRefunded:  60,
+			wantRefundAPI: 2,
+		},
+		{
+			name:          "transaction lost",
+			lost:          true,
+			refunds:       []refund{{reference: "r1", amount: 60, wantErr: provider.ErrNotFound}},
+			wantRefundAPI: 1,
+		},
+		{
+			name:          "legacy payment without transaction",
+			legacy:        true,
+			refunds:       []refund{{reference: "r1", amount: 60}},
+			wantRefundAPI: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := provider.NewSimulator(config.SimulatorConfig{})
+			payment := newTestPayment(model.PaymentStatusCaptured, 100)
+			reference := payment.OrderID.String()
+			if !tt.legacy {
+				tx, err := sim.Authorize(context.Background(), provider.AuthorizeRequest{Reference: reference, Amount: 100})
+				if err != nil {
+					t.Fatalf("Authorize() error = %v", err)
+				}
+				if _, err := sim.Capture(context.Background(), reference, 100); err != nil {
+					t.Fatalf("Capture() error = %v", err)
+				}
+				payment.TransactionID = tx.TransactionID
+			}
+			if tt.lost {
+				sim.Reset()
+			}
+
+			for i, r := range tt.refunds {
+				sim.Script(provider.OpRefund, r.script...)
+				err := refundPayment(context.Background(), sim, payment, r.reference, r.amount)
+				switch {
+				case r.wantErr != nil && !errors.Is(err, r.wantErr):
+					t.Fatalf("refund %d: error = %v, want %v", i, err, r.wantErr)
+				case r.wantFail && err == nil:
+					t.Fatalf("refund %d: error = nil, want an error", i)
+				case r.wantErr == nil && !r.wantFail && err != nil:
+					t.Fatalf("refund %d: error = %v", i, err)
+				}
+			}
+
+			refundCalls := 0
+			for _, call := range sim.Calls() {
+				if call.Operation == provider.OpRefund {
+					refundCalls++
+				}
+			}
+			if refundCalls != tt.wantRefundAPI {
+				t.Errorf("refund calls = %d, want %d", refundCalls, tt.wantRefundAPI)
+			}
+			if tt.legacy || tt.lost {
+				return
+			}
+			tx, err := sim.Query(context.Background(), reference)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if tx.RefundedAmount != tt.wantRefunded {
+				t.Errorf("refunded amount = %d, want %d", tx.RefundedAmount, tt.wantRefunded)
+			}
+		})
+	}
+}